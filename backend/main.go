@@ -10,7 +10,9 @@ import (
 	"github.com/labstack/echo/v4/middleware"
 
 	"google-devjam-backend/router/auth"
+	"google-devjam-backend/router/exercise"
 	"google-devjam-backend/router/news"
+	"google-devjam-backend/router/study"
 	"google-devjam-backend/router/user"
 	"google-devjam-backend/router/vocabulary"
 	mongoUtils "google-devjam-backend/utils/mongodb"
@@ -27,6 +29,31 @@ func main() {
 		log.Fatalf("Failed to connect to MongoDB: %v", err)
 	}
 
+	// Ensure unique indexes exist before serving requests; the write paths rely on them to prevent duplicates,
+	// so existing duplicate records must be removed before the server can start
+	if err := mongoUtils.EnsureIndexes(); err != nil {
+		log.Fatalf("Failed to ensure MongoDB indexes: %v", err)
+	}
+
+	// Jobs cannot survive a restart, so release any the previous process left behind
+	if err := news.FailInterruptedNewsJobs(); err != nil {
+		log.Printf("Warning: Failed to clean up interrupted news jobs: %v", err)
+	}
+
+	// Older articles stored their level as a string, which the news list filters cannot match
+	if err := news.NormalizeNewsLevels(); err != nil {
+		log.Printf("Warning: Failed to normalize news levels: %v", err)
+	}
+
+	// Pre-generate news for active users in the background
+	news.StartNewsScheduler()
+
+	// Collect real articles from the configured feeds for level-adapted rewrites
+	news.StartFeedIngestion()
+
+	// Remove the audio of long-archived news from S3
+	news.StartAudioCleanup()
+
 	// Create echo instance
 	e := echo.New()
 
@@ -44,7 +71,9 @@ func main() {
 	e.GET("/", hello)
 	e.GET("/health", health)
 	auth.InitRoutes(e)
+	exercise.InitRoutes(e)
 	news.InitRoutes(e)
+	study.InitRoutes(e)
 	vocabulary.InitRoutes(e)
 	user.InitUserRouter(e)
 
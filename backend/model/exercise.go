@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+type ListeningExercise struct {
+	ID         string     `json:"id" bson:"_id"`
+	UserID     string     `json:"user_id" bson:"user_id"`
+	WordID     string     `json:"word_id" bson:"word_id"`
+	Mode       string     `json:"mode" bson:"mode"` // "word" or "sentence"
+	Text       string     `json:"-" bson:"text"`    // Hidden from the client until the answer is checked
+	AudioURL   string     `json:"audio_url" bson:"audio_url"`
+	AudioKey   string     `json:"audio_key" bson:"audio_key"`
+	Answer     string     `json:"answer,omitempty" bson:"answer,omitempty"`
+	Correct    *bool      `json:"correct,omitempty" bson:"correct,omitempty"`
+	CreatedAt  time.Time  `json:"created_at" bson:"created_at"`
+	AnsweredAt *time.Time `json:"answered_at,omitempty" bson:"answered_at,omitempty"`
+}
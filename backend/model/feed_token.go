@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// FeedToken lets feed readers and podcast apps, which cannot log in, fetch one user's news feeds.
+// Only a hash of the token is stored; the token itself is shown once, when it is created.
+// Each user has at most one token, so creating a new one revokes the old.
+type FeedToken struct {
+	ID         string     `json:"id" bson:"_id"`
+	UserID     string     `json:"user_id" bson:"user_id"`
+	TokenHash  string     `json:"-" bson:"token_hash"`
+	CreatedAt  time.Time  `json:"created_at" bson:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" bson:"last_used_at,omitempty"`
+}
@@ -5,76 +5,106 @@ import (
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
+
+	"google-devjam-backend/utils/nlp"
 )
 
 type News struct {
-	ID         string    `json:"id" bson:"_id"`
-	UserID     string    `json:"user_id" bson:"user_id"`
-	Title      string    `json:"title" bson:"title"`
-	Content    string    `json:"content" bson:"content"`
-	Level      int       `json:"level" bson:"level"`
-	Keywords   []string  `json:"keywords" bson:"keywords"`
-	WordInNews []string  `json:"word_in_news" bson:"word_in_news"`
-	Source     []string  `json:"source" bson:"source"`
-	AudioURL   string    `json:"audio_url,omitempty" bson:"audio_url,omitempty"`
-	AudioKey   string    `json:"audio_key,omitempty" bson:"audio_key,omitempty"`
-	CreatedAt  time.Time `json:"created_at" bson:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at" bson:"updated_at"`
+	ID             string            `json:"id" bson:"_id"`
+	UserID         string            `json:"user_id" bson:"user_id"`
+	Title          string            `json:"title" bson:"title"`
+	Content        string            `json:"content" bson:"content"`
+	Level          int               `json:"level" bson:"level"`                                       // Measured from Readability when available
+	ReportedLevel  int               `json:"reported_level,omitempty" bson:"reported_level,omitempty"` // Level Gemini claimed to write at
+	Readability    *nlp.Readability  `json:"readability,omitempty" bson:"readability,omitempty"`
+	Keywords       []string          `json:"keywords" bson:"keywords"`
+	WordInNews     []string          `json:"word_in_news" bson:"word_in_news"`
+	VocabCoverage  float64           `json:"vocab_coverage" bson:"vocab_coverage"`                     // Share of the requested learn and review words that appear in Content, 0 to 1
+	MissingWords   []string          `json:"missing_words,omitempty" bson:"missing_words,omitempty"`   // Requested words the article still does not use
+	Source         []string          `json:"source" bson:"source"`                                     // Source names, for display
+	Sources        []NewsSource      `json:"sources,omitempty" bson:"sources,omitempty"`               // Pages found by Google Search, with the claims they back
+	SearchQueries  []string          `json:"search_queries,omitempty" bson:"search_queries,omitempty"` // What Gemini searched for while writing
+	Original       *NewsOriginal     `json:"original,omitempty" bson:"original,omitempty"`             // Set when the article is a rewrite of a real one
+	Summary        string            `json:"summary,omitempty" bson:"summary,omitempty"`               // One-line summary of the article
+	HighlightSpans []HighlightSpan   `json:"highlight_spans,omitempty" bson:"highlight_spans"`         // Where vocabulary words and keywords occur in Content; stored even when empty, to mark them computed
+	Questions      []NewsQuestion    `json:"questions,omitempty" bson:"questions,omitempty"`           // Comprehension and vocabulary questions
+	Gloss          []GlossedSentence `json:"-" bson:"gloss,omitempty"`                                 // Cached translations, served by GET /news/:id/gloss
+	AudioURL       string            `json:"audio_url,omitempty" bson:"audio_url,omitempty"`
+	AudioKey       string            `json:"audio_key,omitempty" bson:"audio_key,omitempty"`
+	CaptionsURL    string            `json:"captions_url,omitempty" bson:"captions_url,omitempty"` // WebVTT track for the audio, with estimated word times
+	CaptionsKey    string            `json:"captions_key,omitempty" bson:"captions_key,omitempty"`
+	AudioTimings   []TimedSentence   `json:"audio_timings,omitempty" bson:"audio_timings,omitempty"` // When each sentence is spoken in the audio, with estimated word times
+	PoolID         string            `json:"pool_id,omitempty" bson:"pool_id,omitempty"`             // Set when the article is in the shared pool, which then owns the audio
+	OpenedAt       *time.Time        `json:"opened_at,omitempty" bson:"opened_at,omitempty"`         // When the user first opened the article
+	ReadAt         *time.Time        `json:"read_at,omitempty" bson:"read_at,omitempty"`             // When the user marked the article read; cleared by marking it unread
+	ReadProgress   float64           `json:"read_progress" bson:"read_progress,omitempty"`           // How far the user got through the article, 0 to 1
+	FavoritedAt    *time.Time        `json:"favorited_at,omitempty" bson:"favorited_at,omitempty"`   // Set while the article is a favorite
+	ArchivedAt     *time.Time        `json:"archived_at,omitempty" bson:"archived_at,omitempty"`     // Set when the article is archived; kept readable in history
+	CreatedAt      time.Time         `json:"created_at" bson:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at" bson:"updated_at"`
+}
+
+const (
+	HighlightVocabulary = "vocabulary"
+	HighlightKeyword    = "keyword"
+)
+
+// HighlightSpan locates a vocabulary word or keyword in an article's content by rune offsets, End exclusive.
+// Text is the form that appears in the article, which may be an inflection of Word ("studied" for "study").
+type HighlightSpan struct {
+	Word  string `json:"word" bson:"word"`
+	Kind  string `json:"kind" bson:"kind"` // "vocabulary" or "keyword"
+	Start int    `json:"start" bson:"start"`
+	End   int    `json:"end" bson:"end"`
+	Text  string `json:"text" bson:"text"`
 }
 
-// UnmarshalBSON implements custom BSON unmarshaling for News
+// UnmarshalBSON implements custom BSON unmarshaling for News.
+// Older documents store Level as a string, so it is normalized to an int before decoding.
 func (n *News) UnmarshalBSON(data []byte) error {
-	// Define a temporary struct with Level as interface{} to handle both string and int
-	type TempNews struct {
-		ID         string      `bson:"_id"`
-		UserID     string      `bson:"user_id"`
-		Title      string      `bson:"title"`
-		Content    string      `bson:"content"`
-		Level      interface{} `bson:"level"`
-		Keywords   []string    `bson:"keywords"`
-		WordInNews []string    `bson:"word_in_news"`
-		Source     []string    `bson:"source"`
-		AudioURL   string      `bson:"audio_url,omitempty"`
-		AudioKey   string      `bson:"audio_key,omitempty"`
-		CreatedAt  time.Time   `bson:"created_at"`
-		UpdatedAt  time.Time   `bson:"updated_at"`
+	var doc bson.D
+	if err := bson.Unmarshal(data, &doc); err != nil {
+		return err
 	}
 
-	var temp TempNews
-	if err := bson.Unmarshal(data, &temp); err != nil {
+	hasLevel := false
+	for i := range doc {
+		if doc[i].Key == "level" {
+			doc[i].Value = normalizeLevel(doc[i].Value)
+			hasLevel = true
+		}
+	}
+	if !hasLevel {
+		doc = append(doc, bson.E{Key: "level", Value: 1})
+	}
+
+	normalized, err := bson.Marshal(doc)
+	if err != nil {
 		return err
 	}
 
-	// Copy all fields except Level
-	n.ID = temp.ID
-	n.UserID = temp.UserID
-	n.Title = temp.Title
-	n.Content = temp.Content
-	n.Keywords = temp.Keywords
-	n.WordInNews = temp.WordInNews
-	n.Source = temp.Source
-	n.AudioURL = temp.AudioURL
-	n.AudioKey = temp.AudioKey
-	n.CreatedAt = temp.CreatedAt
-	n.UpdatedAt = temp.UpdatedAt
+	// Decode through an alias type so this method is not called recursively
+	type newsAlias News
+	return bson.Unmarshal(normalized, (*newsAlias)(n))
+}
 
-	// Handle Level conversion
-	switch v := temp.Level.(type) {
+// normalizeLevel converts a stored level of any type to an int
+func normalizeLevel(value interface{}) int {
+	switch v := value.(type) {
 	case int:
-		n.Level = v
+		return v
 	case int32:
-		n.Level = int(v)
+		return int(v)
 	case int64:
-		n.Level = int(v)
+		return int(v)
+	case float64:
+		return int(v)
 	case string:
 		if level, err := strconv.Atoi(v); err == nil {
-			n.Level = level
-		} else {
-			n.Level = 1 // Default to level 1 if conversion fails
+			return level
 		}
+		return 1 // Default to level 1 if conversion fails
 	default:
-		n.Level = 1 // Default to level 1 for any other type
+		return 1 // Default to level 1 for any other type
 	}
-
-	return nil
 }
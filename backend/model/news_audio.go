@@ -0,0 +1,25 @@
+package model
+
+// TimedWord is a word of an article located in the content (rune offsets, End exclusive) and roughly in its audio.
+// Its times are estimates: the sentence's time is spread over its words by syllable count, not measured from the audio.
+// The BSON keys predate the rename and are kept so stored timings still decode.
+type TimedWord struct {
+	Text               string  `json:"text" bson:"text"`
+	Start              int     `json:"start" bson:"start"`
+	End                int     `json:"end" bson:"end"`
+	EstimatedStartTime float64 `json:"estimated_start_time" bson:"start_time"`
+	EstimatedEndTime   float64 `json:"estimated_end_time" bson:"end_time"`
+}
+
+// TimedSentence is a sentence of an article located in both the content and its audio, with its words.
+// Sentence times are reported by the TTS server's timed synthesis; Estimated is set when the server could not
+// report them and they were estimated from sentence lengths and pauses in the audio instead.
+type TimedSentence struct {
+	Text      string      `json:"text" bson:"text"`
+	Start     int         `json:"start" bson:"start"`
+	End       int         `json:"end" bson:"end"`
+	StartTime float64     `json:"start_time" bson:"start_time"`
+	EndTime   float64     `json:"end_time" bson:"end_time"`
+	Estimated bool        `json:"estimated" bson:"estimated,omitempty"`
+	Words     []TimedWord `json:"words" bson:"words"`
+}
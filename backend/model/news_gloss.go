@@ -0,0 +1,18 @@
+package model
+
+// GlossedWord is a short in-context gloss of a hard word, located by rune offsets in the article content
+type GlossedWord struct {
+	Word  string `json:"word" bson:"word"`
+	Gloss string `json:"gloss" bson:"gloss"`
+	Start int    `json:"start" bson:"start"`
+	End   int    `json:"end" bson:"end"`
+}
+
+// GlossedSentence is one sentence of an article with its translation, located by rune offsets in the content
+type GlossedSentence struct {
+	Start       int           `json:"start" bson:"start"`
+	End         int           `json:"end" bson:"end"`
+	Text        string        `json:"text" bson:"text"`
+	Translation string        `json:"translation" bson:"translation"`
+	Glosses     []GlossedWord `json:"glosses" bson:"glosses"`
+}
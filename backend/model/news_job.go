@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+const (
+	NewsJobPending   = "pending"
+	NewsJobRunning   = "running"
+	NewsJobCompleted = "completed"
+	NewsJobFailed    = "failed"
+)
+
+const (
+	NewsArticlePending    = "pending"
+	NewsArticleGenerating = "generating" // Waiting for the article text
+	NewsArticleAudio      = "audio"      // Text is ready, synthesizing and uploading audio
+	NewsArticleCompleted  = "completed"
+	NewsArticleFailed     = "failed"
+)
+
+// NewsJobArticle tracks one article of a generation job
+type NewsJobArticle struct {
+	Status string `json:"status" bson:"status"`
+	NewsID string `json:"news_id,omitempty" bson:"news_id,omitempty"`
+	Title  string `json:"title,omitempty" bson:"title,omitempty"`
+	Error  string `json:"error,omitempty" bson:"error,omitempty"`
+}
+
+type NewsGenerationJob struct {
+	ID         string           `json:"id" bson:"_id"`
+	UserID     string           `json:"user_id" bson:"user_id"`
+	Status     string           `json:"status" bson:"status"`
+	Force      bool             `json:"force" bson:"force"`         // Replaces the user's existing news instead of adding to it
+	Scheduled  bool             `json:"scheduled" bson:"scheduled"` // Started by the background scheduler rather than a request
+	Articles   []NewsJobArticle `json:"articles" bson:"articles"`
+	Error      string           `json:"error,omitempty" bson:"error,omitempty"`
+	CreatedAt  time.Time        `json:"created_at" bson:"created_at"`
+	UpdatedAt  time.Time        `json:"updated_at" bson:"updated_at"`
+	FinishedAt *time.Time       `json:"finished_at,omitempty" bson:"finished_at,omitempty"`
+}
+
+// IsFinished reports whether the job has stopped running
+func (j *NewsGenerationJob) IsFinished() bool {
+	return j.Status == NewsJobCompleted || j.Status == NewsJobFailed
+}
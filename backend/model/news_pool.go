@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+
+	"google-devjam-backend/utils/nlp"
+)
+
+// PooledArticle is a generated article shared between users. Each generated article is added to the pool and copied
+// into the news of other users whose level, interests and due words it fits, so one Gemini call can serve many users.
+// The pool owns the audio and captions; user copies only reference them.
+type PooledArticle struct {
+	ID            string           `json:"id" bson:"_id"`
+	Title         string           `json:"title" bson:"title"`
+	Content       string           `json:"content" bson:"content"`
+	Level         int              `json:"level" bson:"level"` // Measured against common words only, not any user's vocabulary
+	Readability   *nlp.Readability `json:"readability,omitempty" bson:"readability,omitempty"`
+	Keywords      []string         `json:"keywords" bson:"keywords"`
+	Topics        []string         `json:"topics" bson:"topics"`         // Lowercased interests the article is about
+	Vocabulary    []string         `json:"vocabulary" bson:"vocabulary"` // Every possible base form of every word in Content, for matching due words
+	Source        []string         `json:"source" bson:"source"`
+	Sources       []NewsSource     `json:"sources,omitempty" bson:"sources,omitempty"`
+	SearchQueries []string         `json:"search_queries,omitempty" bson:"search_queries,omitempty"`
+	Original      *NewsOriginal    `json:"original,omitempty" bson:"original,omitempty"`
+	Summary       string           `json:"summary,omitempty" bson:"summary,omitempty"`
+	Questions     []NewsQuestion   `json:"questions,omitempty" bson:"questions,omitempty"`
+	AudioURL      string           `json:"audio_url,omitempty" bson:"audio_url,omitempty"`
+	AudioKey      string           `json:"audio_key,omitempty" bson:"audio_key,omitempty"`
+	CaptionsURL   string           `json:"captions_url,omitempty" bson:"captions_url,omitempty"`
+	CaptionsKey   string           `json:"captions_key,omitempty" bson:"captions_key,omitempty"`
+	AudioTimings  []TimedSentence  `json:"audio_timings,omitempty" bson:"audio_timings,omitempty"`
+	KidSafe       bool             `json:"kid_safe" bson:"kid_safe"`         // Passes the kid-safe content checks, so it may be served to kid-safe users
+	ServedCount   int              `json:"served_count" bson:"served_count"` // Users the article was copied to, besides the one it was generated for
+	CreatedAt     time.Time        `json:"created_at" bson:"created_at"`
+	UpdatedAt     time.Time        `json:"updated_at" bson:"updated_at"`
+}
+
+// PoolDelivery records that a user received a pooled article, so it is never served to them again.
+// Deliveries outlive the user's copy, which may be deleted.
+type PoolDelivery struct {
+	ID        string    `json:"id" bson:"_id"`
+	UserID    string    `json:"user_id" bson:"user_id"`
+	PoolID    string    `json:"pool_id" bson:"pool_id"`
+	NewsID    string    `json:"news_id" bson:"news_id"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+}
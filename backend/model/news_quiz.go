@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+type NewsQuestion struct {
+	ID          string   `json:"id" bson:"id"`
+	Type        string   `json:"type" bson:"type"` // "multiple_choice", "short_answer" or "vocabulary"
+	Question    string   `json:"question" bson:"question"`
+	Options     []string `json:"options,omitempty" bson:"options,omitempty"`
+	Word        string   `json:"word,omitempty" bson:"word,omitempty"` // Target word of a vocabulary question
+	Answer      string   `json:"-" bson:"answer"`                      // Hidden from the client until the answers are graded
+	Explanation string   `json:"-" bson:"explanation"`
+}
+
+type NewsAnswerResult struct {
+	QuestionID  string `json:"question_id" bson:"question_id"`
+	Answer      string `json:"answer" bson:"answer"`
+	Correct     bool   `json:"correct" bson:"correct"`
+	Expected    string `json:"expected" bson:"expected"` // The correct option, or a reference answer for short answers
+	Explanation string `json:"explanation" bson:"explanation"`
+	Feedback    string `json:"feedback,omitempty" bson:"feedback,omitempty"` // LLM feedback on short answers
+}
+
+// NewsQuizAttempt is one graded submission of answers to an article's questions
+type NewsQuizAttempt struct {
+	ID        string             `json:"id" bson:"_id"`
+	UserID    string             `json:"user_id" bson:"user_id"`
+	NewsID    string             `json:"news_id" bson:"news_id"`
+	Results   []NewsAnswerResult `json:"results" bson:"results"`
+	Correct   int                `json:"correct" bson:"correct"`
+	Total     int                `json:"total" bson:"total"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+}
@@ -0,0 +1,17 @@
+package model
+
+// NewsSource is a web page Google Search returned while the article was written, with the claims it backs
+type NewsSource struct {
+	URL    string      `json:"url" bson:"url"`
+	Title  string      `json:"title" bson:"title"` // Usually the publisher's domain
+	Claims []NewsClaim `json:"claims,omitempty" bson:"claims,omitempty"`
+}
+
+// NewsClaim is a passage of the article backed by a source. Start and End locate it in the article's content by
+// rune offsets, End exclusive; End is 0 when the passage was reworded after generation and could not be found.
+type NewsClaim struct {
+	Text       string  `json:"text" bson:"text"`
+	Start      int     `json:"start" bson:"start"`
+	End        int     `json:"end" bson:"end"`
+	Confidence float64 `json:"confidence,omitempty" bson:"confidence,omitempty"` // Gemini's confidence that the source supports the claim, 0 to 1
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// QuarantinedNews is a generated article that failed content safety checks. It is never shown to the user;
+// it is kept so the reasons can be reviewed and the blocklist tuned.
+type QuarantinedNews struct {
+	ID         string    `json:"id" bson:"_id"`
+	UserID     string    `json:"user_id" bson:"user_id"`
+	News       News      `json:"news" bson:"news"`
+	Checker    string    `json:"checker" bson:"checker"` // "local" for the blocklist and heuristics, "llm" for the Gemini classifier
+	Categories []string  `json:"categories" bson:"categories"`
+	Reasons    []string  `json:"reasons" bson:"reasons"`
+	KidSafe    bool      `json:"kid_safe" bson:"kid_safe"` // Whether the stricter kid-safe rules applied
+	CreatedAt  time.Time `json:"created_at" bson:"created_at"`
+}
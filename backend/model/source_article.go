@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// SourceArticle is a real article ingested from a configured RSS or Atom feed.
+// News rewritten from it at a learner's level links back to it through NewsOriginal.
+type SourceArticle struct {
+	ID          string    `json:"id" bson:"_id"`
+	FeedURL     string    `json:"feed_url" bson:"feed_url"`
+	FeedTitle   string    `json:"feed_title" bson:"feed_title"` // The publisher, as the feed names itself
+	URL         string    `json:"url" bson:"url"`
+	GUID        string    `json:"guid" bson:"guid"`
+	Title       string    `json:"title" bson:"title"`
+	Text        string    `json:"text" bson:"text"` // Plain text, paragraphs separated by blank lines
+	PublishedAt time.Time `json:"published_at" bson:"published_at"`
+	FetchedAt   time.Time `json:"fetched_at" bson:"fetched_at"`
+}
+
+// NewsOriginal credits the real article a news article was rewritten from
+type NewsOriginal struct {
+	SourceArticleID string    `json:"source_article_id" bson:"source_article_id"`
+	URL             string    `json:"url" bson:"url"`
+	Title           string    `json:"title" bson:"title"`
+	Publisher       string    `json:"publisher" bson:"publisher"`
+	PublishedAt     time.Time `json:"published_at" bson:"published_at"`
+}
@@ -9,13 +9,39 @@ type User struct {
 	Password    string    `json:"password"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+
+	LastActiveAt *time.Time `json:"last_active_at,omitempty" bson:"last_active_at,omitempty"` // Last authenticated request, recorded at most hourly
 }
 
 type UserPreferences struct {
-	ID        string    `json:"id" bson:"_id"`
-	UserID    string    `json:"user_id" bson:"user_id"`
-	Level     int       `json:"level" bson:"level"`
-	Interests []string  `json:"interests" bson:"interests"`
-	CreatedAt time.Time `json:"created_at" bson:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+	ID            string    `json:"id" bson:"_id"`
+	UserID        string    `json:"user_id" bson:"user_id"`
+	Level         int       `json:"level" bson:"level"`
+	Interests     []string  `json:"interests" bson:"interests"`
+	DailyGoal     int       `json:"daily_goal,omitempty" bson:"daily_goal,omitempty"`           // Words to practice per day
+	DailyNewWords int       `json:"daily_new_words,omitempty" bson:"daily_new_words,omitempty"` // New words to introduce per day
+	KidSafe       bool      `json:"kid_safe" bson:"kid_safe,omitempty"`                         // Apply stricter content checks to the user's news
+	CreatedAt     time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+const (
+	DefaultDailyGoal     = 20
+	DefaultDailyNewWords = 5
+)
+
+// GetDailyGoal returns the user's daily practice goal, falling back to the default when unset
+func (p *UserPreferences) GetDailyGoal() int {
+	if p == nil || p.DailyGoal <= 0 {
+		return DefaultDailyGoal
+	}
+	return p.DailyGoal
+}
+
+// GetDailyNewWords returns how many new words to introduce per day, falling back to the default when unset
+func (p *UserPreferences) GetDailyNewWords() int {
+	if p == nil || p.DailyNewWords <= 0 {
+		return DefaultDailyNewWords
+	}
+	return p.DailyNewWords
 }
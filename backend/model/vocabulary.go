@@ -29,6 +29,8 @@ type WordExample struct {
 	ID       string `json:"id" bson:"_id"`
 	WordID   string `json:"word_id" bson:"word_id"`
 	Sentence string `json:"sentence" bson:"sentence"`
+	UserID   string `json:"user_id,omitempty" bson:"user_id,omitempty"` // Set for personal examples, which only their owner sees
+	NewsID   string `json:"news_id,omitempty" bson:"news_id,omitempty"` // Article the example sentence was taken from
 }
 
 type RecommendWord struct {
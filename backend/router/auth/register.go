@@ -102,6 +102,12 @@ func Register(c echo.Context) error {
 	// Insert user into database
 	_, err = collection.InsertOne(context.Background(), user)
 	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			// Another registration with the same email won the race
+			return c.JSON(http.StatusConflict, map[string]string{
+				"error": "User with this email already exists",
+			})
+		}
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": "Failed to create user",
 		})
@@ -0,0 +1,259 @@
+package exercise
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"google-devjam-backend/model"
+	"google-devjam-backend/router/vocabulary"
+	"google-devjam-backend/utils/encrypt"
+	"google-devjam-backend/utils/middleware"
+	"google-devjam-backend/utils/mongodb"
+	"google-devjam-backend/utils/nlp"
+	"google-devjam-backend/utils/services"
+)
+
+const (
+	ListeningModeWord     = "word"
+	ListeningModeSentence = "sentence"
+)
+
+type CreateListeningRequest struct {
+	WordID string `json:"word_id" validate:"required"`
+	Mode   string `json:"mode"` // "word" (default) or "sentence"
+}
+
+type ListeningExerciseResponse struct {
+	Exercise model.ListeningExercise `json:"exercise"`
+}
+
+type CheckListeningRequest struct {
+	Answer string `json:"answer"`
+}
+
+type CheckListeningResponse struct {
+	Correct    bool              `json:"correct"`
+	Expected   string            `json:"expected"`
+	Answer     string            `json:"answer"`
+	Diff       []nlp.DiffSegment `json:"diff"`
+	LearnCount int               `json:"learn_count"`
+	Fluency    int               `json:"fluency"`
+}
+
+// CreateListeningExercise prepares a dictation exercise for a saved word, with TTS audio of the word or an example sentence
+func CreateListeningExercise(c echo.Context) error {
+	// Get user info from context
+	userID, _ := middleware.GetUserFromContext(c)
+	if userID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "User not authenticated",
+		})
+	}
+
+	var req CreateListeningRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request format",
+		})
+	}
+
+	wordID := req.WordID
+	if wordID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Word ID is required",
+		})
+	}
+
+	mode := req.Mode
+	if mode == "" {
+		mode = ListeningModeWord
+	}
+	if mode != ListeningModeWord && mode != ListeningModeSentence {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Mode must be 'word' or 'sentence'",
+		})
+	}
+
+	word, err := getOwnedWord(userID, wordID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Word not found in your vocabulary",
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Database error",
+		})
+	}
+
+	// Pick the text the user will hear
+	text := word.Word
+	if mode == ListeningModeSentence {
+		examples, err := vocabulary.GetWordExamples(word.ID, userID)
+		if err != nil || len(examples) == 0 {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "This word has no example sentences to practice with",
+			})
+		}
+		text = examples[rand.Intn(len(examples))].Sentence
+	}
+
+	audioService, err := services.SharedAudioService()
+	if err != nil {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{
+			"error": "Audio service is not available",
+		})
+	}
+
+	audioURL, audioKey, err := audioService.GetOrCreateCachedAudio(text)
+	if err != nil {
+		log.Printf("Warning: Failed to get audio for listening exercise: %v", err)
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{
+			"error": "Failed to generate audio",
+		})
+	}
+
+	exerciseID, err := encrypt.GenerateSnowflakeID()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to generate exercise ID",
+		})
+	}
+
+	exercise := model.ListeningExercise{
+		ID:        exerciseID,
+		UserID:    userID,
+		WordID:    word.ID,
+		Mode:      mode,
+		Text:      text,
+		AudioURL:  audioURL,
+		AudioKey:  audioKey,
+		CreatedAt: time.Now(),
+	}
+
+	exercisesCollection := mongodb.GetCollection("listening_exercises")
+	if exercisesCollection == nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Database connection error",
+		})
+	}
+
+	if _, err := exercisesCollection.InsertOne(context.Background(), exercise); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to create exercise",
+		})
+	}
+
+	return c.JSON(http.StatusCreated, ListeningExerciseResponse{
+		Exercise: exercise,
+	})
+}
+
+// CheckListeningAnswer checks a typed answer against the audio text and updates the word's learning progress
+func CheckListeningAnswer(c echo.Context) error {
+	// Get user info from context
+	userID, _ := middleware.GetUserFromContext(c)
+	if userID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "User not authenticated",
+		})
+	}
+
+	exerciseID := c.Param("id")
+	if exerciseID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Exercise ID is required",
+		})
+	}
+
+	var req CheckListeningRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request format",
+		})
+	}
+
+	exercisesCollection := mongodb.GetCollection("listening_exercises")
+	if exercisesCollection == nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Database connection error",
+		})
+	}
+
+	var exercise model.ListeningExercise
+	err := exercisesCollection.FindOne(context.Background(), bson.M{
+		"_id":     exerciseID,
+		"user_id": userID,
+	}).Decode(&exercise)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Exercise not found",
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Database error",
+		})
+	}
+
+	if exercise.AnsweredAt != nil {
+		return c.JSON(http.StatusConflict, map[string]string{
+			"error": "Exercise has already been answered",
+		})
+	}
+
+	// Compare ignoring case and punctuation, then show exactly which characters differ
+	expected := nlp.NormalizeForComparison(exercise.Text)
+	answer := nlp.NormalizeForComparison(req.Answer)
+	correct := expected == answer
+
+	now := time.Now()
+	result, err := exercisesCollection.UpdateOne(context.Background(),
+		bson.M{"_id": exercise.ID, "answered_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{
+			"answer":      strings.TrimSpace(req.Answer),
+			"correct":     correct,
+			"answered_at": now,
+		}},
+	)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to save answer",
+		})
+	}
+	if result.MatchedCount == 0 {
+		// A concurrent request answered first; only one attempt counts toward progress
+		return c.JSON(http.StatusConflict, map[string]string{
+			"error": "Exercise has already been answered",
+		})
+	}
+
+	learnCount, fluency, err := vocabulary.UpdateLearningProgress(userID, exercise.WordID, correct)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Word not found in your vocabulary",
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to update learning progress",
+		})
+	}
+
+	return c.JSON(http.StatusOK, CheckListeningResponse{
+		Correct:    correct,
+		Expected:   exercise.Text,
+		Answer:     req.Answer,
+		Diff:       nlp.CharDiff(expected, answer),
+		LearnCount: learnCount,
+		Fluency:    fluency,
+	})
+}
@@ -0,0 +1,19 @@
+package exercise
+
+import (
+	"google-devjam-backend/utils/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+func InitRoutes(e *echo.Echo) {
+	// All exercise routes require authentication
+	exerciseGroup := e.Group("/exercise", middleware.JWTMiddleware())
+
+	// Productive practice
+	exerciseGroup.POST("/sentence", GradeSentence) // POST /exercise/sentence - Grade a sentence the user wrote with a saved word
+
+	// Listening and dictation practice
+	exerciseGroup.POST("/listening", CreateListeningExercise)         // POST /exercise/listening - Create a dictation exercise with TTS audio
+	exerciseGroup.POST("/listening/:id/answer", CheckListeningAnswer) // POST /exercise/listening/:id/answer - Check the typed answer
+}
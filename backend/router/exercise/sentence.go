@@ -0,0 +1,117 @@
+package exercise
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"google-devjam-backend/model"
+	"google-devjam-backend/router/vocabulary"
+	"google-devjam-backend/utils/gemini"
+	"google-devjam-backend/utils/middleware"
+)
+
+const (
+	// goodSentenceScore is the naturalness score a correct sentence needs before it can be saved as an example
+	goodSentenceScore = 7
+	// gradeTimeout bounds the Gemini grading call
+	gradeTimeout = 30 * time.Second
+)
+
+type GradeSentenceRequest struct {
+	WordID      string `json:"word_id" validate:"required"`
+	Sentence    string `json:"sentence" validate:"required"`
+	SaveExample bool   `json:"save_example"` // Save the sentence as a personal example if it is good enough
+}
+
+type GradeSentenceResponse struct {
+	Result       gemini.SentenceGradeResult `json:"result"`
+	LearnCount   int                        `json:"learn_count"`
+	Fluency      int                        `json:"fluency"`
+	SavedExample *model.WordExample         `json:"saved_example,omitempty"`
+}
+
+// GradeSentence grades a sentence the user wrote with one of their saved words and updates its learning progress
+func GradeSentence(c echo.Context) error {
+	// Get user info from context
+	userID, _ := middleware.GetUserFromContext(c)
+	if userID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "User not authenticated",
+		})
+	}
+
+	var req GradeSentenceRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request format",
+		})
+	}
+
+	sentence := strings.TrimSpace(req.Sentence)
+	if req.WordID == "" || sentence == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Word ID and sentence are required",
+		})
+	}
+
+	if len(sentence) > 500 {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Sentence must be at most 500 characters",
+		})
+	}
+
+	word, err := getOwnedWord(userID, req.WordID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Word not found in your vocabulary",
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Database error",
+		})
+	}
+
+	// Grade the sentence with Gemini
+	ctx, cancel := context.WithTimeout(c.Request().Context(), gradeTimeout)
+	defer cancel()
+
+	result, err := gemini.GradeSentence(ctx, word.Word, sentence)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to grade sentence: " + err.Error(),
+		})
+	}
+
+	// Writing a correct sentence counts as a successful practice of the word
+	learnCount, fluency, err := vocabulary.UpdateLearningProgress(userID, word.ID, result.IsCorrect)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to update learning progress",
+		})
+	}
+
+	response := GradeSentenceResponse{
+		Result:     *result,
+		LearnCount: learnCount,
+		Fluency:    fluency,
+	}
+
+	if req.SaveExample && result.IsCorrect && result.NaturalnessScore >= goodSentenceScore {
+		example, err := vocabulary.AddPersonalExample(userID, word.ID, "", sentence)
+		if err != nil {
+			// The grade is still useful without the saved example
+			log.Printf("Warning: Failed to save example sentence for word %s: %v", word.ID, err)
+		} else {
+			response.SavedExample = example
+		}
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
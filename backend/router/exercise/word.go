@@ -0,0 +1,36 @@
+package exercise
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"google-devjam-backend/model"
+	"google-devjam-backend/utils/mongodb"
+)
+
+// getOwnedWord loads a word from the global collection, returning mongo.ErrNoDocuments unless it is in the user's vocabulary
+func getOwnedWord(userID, wordID string) (*model.Word, error) {
+	userWordsCollection := mongodb.GetCollection("user_words")
+	wordsCollection := mongodb.GetCollection("words")
+	if userWordsCollection == nil || wordsCollection == nil {
+		return nil, mongo.ErrClientDisconnected
+	}
+
+	var userWord model.UserWord
+	err := userWordsCollection.FindOne(context.Background(), bson.M{
+		"user_id": userID,
+		"word_id": wordID,
+	}).Decode(&userWord)
+	if err != nil {
+		return nil, err
+	}
+
+	var word model.Word
+	if err := wordsCollection.FindOne(context.Background(), bson.M{"_id": wordID}).Decode(&word); err != nil {
+		return nil, err
+	}
+
+	return &word, nil
+}
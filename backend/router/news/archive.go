@@ -0,0 +1,148 @@
+package news
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"google-devjam-backend/model"
+	"google-devjam-backend/utils/mongodb"
+	"google-devjam-backend/utils/services"
+)
+
+const (
+	defaultAudioRetention       = 30 * 24 * time.Hour
+	defaultAudioCleanupInterval = time.Hour
+	// audioCleanupBatchSize bounds how many articles one cleanup pass handles
+	audioCleanupBatchSize = 100
+)
+
+// archiveUserNews moves the user's current news into their history, except the articles in keepIDs
+func archiveUserNews(userID string, keepIDs []string) error {
+	newsCollection := mongodb.GetCollection("news")
+	if newsCollection == nil {
+		return mongo.ErrClientDisconnected
+	}
+
+	now := time.Now()
+	_, err := newsCollection.UpdateMany(
+		context.Background(),
+		bson.M{
+			"_id":         bson.M{"$nin": keepIDs},
+			"user_id":     userID,
+			"archived_at": bson.M{"$exists": false},
+		},
+		bson.M{"$set": bson.M{
+			"archived_at": now,
+			"updated_at":  now,
+		}},
+	)
+	return err
+}
+
+// StartAudioCleanup periodically deletes the S3 audio of articles that have been archived for longer than
+// NEWS_AUDIO_RETENTION (default 720h), and of pooled articles that old which no current article uses.
+// The article text stays readable; only its audio is removed.
+// Set NEWS_AUDIO_RETENTION to "off" to keep archived audio forever.
+func StartAudioCleanup() {
+	if os.Getenv("NEWS_AUDIO_RETENTION") == "off" {
+		log.Println("Archived news audio cleanup is disabled")
+		return
+	}
+
+	retention := durationFromEnv("NEWS_AUDIO_RETENTION", defaultAudioRetention)
+
+	go func() {
+		ticker := time.NewTicker(defaultAudioCleanupInterval)
+		defer ticker.Stop()
+
+		for {
+			if removed, err := cleanupArchivedAudio(time.Now().Add(-retention)); err != nil {
+				log.Printf("Warning: Archived news audio cleanup failed after removing %d files: %v", removed, err)
+			} else if removed > 0 {
+				log.Printf("Removed audio of %d archived news articles", removed)
+			}
+			if removed, err := cleanupPooledAudio(time.Now().Add(-retention)); err != nil {
+				log.Printf("Warning: Pooled news audio cleanup failed after removing %d files: %v", removed, err)
+			} else if removed > 0 {
+				log.Printf("Removed audio of %d pooled news articles", removed)
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// cleanupArchivedAudio deletes the audio of articles archived before the cutoff and clears their audio fields
+func cleanupArchivedAudio(cutoff time.Time) (int, error) {
+	newsCollection := mongodb.GetCollection("news")
+	if newsCollection == nil {
+		return 0, mongo.ErrClientDisconnected
+	}
+
+	cursor, err := newsCollection.Find(
+		context.Background(),
+		bson.M{
+			"archived_at": bson.M{"$lt": cutoff},
+			"audio_key":   bson.M{"$exists": true, "$ne": ""},
+			"pool_id":     bson.M{"$exists": false}, // Shared audio is removed by cleanupPooledAudio
+		},
+		options.Find().
+			SetProjection(bson.M{"_id": 1, "audio_key": 1, "captions_key": 1}).
+			SetLimit(audioCleanupBatchSize),
+	)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(context.Background())
+
+	var archived []model.News
+	if err := cursor.All(context.Background(), &archived); err != nil {
+		return 0, err
+	}
+	if len(archived) == 0 {
+		return 0, nil
+	}
+
+	audioService, err := services.SharedAudioService()
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, news := range archived {
+		if err := audioService.DeleteAudio(news.AudioKey); err != nil {
+			// Leave the fields in place so the next pass retries this file
+			log.Printf("Warning: Failed to delete audio %s of news %s: %v", news.AudioKey, news.ID, err)
+			continue
+		}
+		if news.CaptionsKey != "" {
+			if err := audioService.DeleteAudio(news.CaptionsKey); err != nil {
+				log.Printf("Warning: Failed to delete captions %s of news %s: %v", news.CaptionsKey, news.ID, err)
+				continue
+			}
+		}
+
+		_, err := newsCollection.UpdateOne(
+			context.Background(),
+			bson.M{"_id": news.ID},
+			bson.M{"$unset": bson.M{
+				"audio_url":     "",
+				"audio_key":     "",
+				"captions_url":  "",
+				"captions_key":  "",
+				"audio_timings": "",
+			}},
+		)
+		if err != nil {
+			return removed, err
+		}
+		removed++
+	}
+
+	return removed, nil
+}
@@ -0,0 +1,76 @@
+package news
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"google-devjam-backend/utils/gemini"
+	"google-devjam-backend/utils/nlp"
+)
+
+const (
+	// minVocabCoverage is the share of requested vocabulary an article must use before it is accepted without repair
+	minVocabCoverage = 0.8
+	// maxCoverageRepairs bounds how many times an article is sent back to work in missing words
+	maxCoverageRepairs = 2
+	// coverageRepairTimeout bounds one repair call, which rewrites the whole article
+	coverageRepairTimeout = 90 * time.Second
+)
+
+// measureVocabCoverage returns the share of words that appear in content, inflections included, and the words that do not
+func measureVocabCoverage(content string, words []string) (float64, []string) {
+	if len(words) == 0 {
+		return 1, nil
+	}
+
+	found := make(map[string]bool)
+	for _, match := range nlp.FindWordMatches(content, words) {
+		found[strings.ToLower(match.Word)] = true
+	}
+
+	total := 0
+	seen := make(map[string]bool)
+	var missing []string
+	for _, word := range words {
+		key := strings.ToLower(word)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		total++
+		if !found[key] {
+			missing = append(missing, word)
+		}
+	}
+
+	return float64(total-len(missing)) / float64(total), missing
+}
+
+// ensureVocabCoverage asks Gemini to work missing words into the article until coverage reaches minVocabCoverage.
+// A revision is only kept if it covers more words than the text it replaces, so a bad repair never makes things worse.
+// It returns the final content with its coverage and the words still missing.
+func ensureVocabCoverage(newsID, title, content string, words []string) (string, float64, []string) {
+	coverage, missing := measureVocabCoverage(content, words)
+
+	for attempt := 0; attempt < maxCoverageRepairs && coverage < minVocabCoverage; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), coverageRepairTimeout)
+		revised, err := gemini.ReviseNewsWithWords(ctx, title, content, missing)
+		cancel()
+		if err != nil {
+			log.Printf("Warning: Failed to repair vocabulary coverage of news %s: %v", newsID, err)
+			break
+		}
+
+		revisedCoverage, revisedMissing := measureVocabCoverage(revised, words)
+		if revisedCoverage <= coverage {
+			log.Printf("Warning: Repair of news %s did not improve vocabulary coverage (%.2f)", newsID, coverage)
+			break
+		}
+
+		content, coverage, missing = revised, revisedCoverage, revisedMissing
+	}
+
+	return content, coverage, missing
+}
@@ -0,0 +1,180 @@
+package news
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"google-devjam-backend/model"
+	"google-devjam-backend/utils/middleware"
+	"google-devjam-backend/utils/mongodb"
+)
+
+const (
+	NewsEventSnapshot       = "snapshot"        // Current job state, sent when a client connects
+	NewsEventTextReady      = "text_ready"      // Article text is stored and readable
+	NewsEventAudioUploading = "audio_uploading" // Audio is being synthesized and uploaded
+	NewsEventAudioReady     = "audio_ready"     // Audio is attached to the article
+	NewsEventArticleFailed  = "article_failed"  // One article failed; the rest of the job continues
+	NewsEventJobCompleted   = "job_completed"
+	NewsEventJobFailed      = "job_failed"
+)
+
+// sseHeartbeatInterval keeps idle connections from being closed by proxies
+const sseHeartbeatInterval = 15 * time.Second
+
+type NewsJobEvent struct {
+	Type  string                   `json:"type"`
+	JobID string                   `json:"job_id"`
+	Index int                      `json:"index"` // Article index within the job, -1 for job-level events
+	News  *model.News              `json:"news,omitempty"`
+	Job   *model.NewsGenerationJob `json:"job,omitempty"`
+	Error string                   `json:"error,omitempty"`
+}
+
+// newsEventHub fans out job events to the clients streaming them
+type newsEventHub struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan NewsJobEvent]struct{}
+}
+
+var jobEvents = &newsEventHub{
+	subscribers: make(map[string]map[chan NewsJobEvent]struct{}),
+}
+
+// subscribe registers a listener for a job's events; the returned function must be called to release it
+func (h *newsEventHub) subscribe(jobID string) (<-chan NewsJobEvent, func()) {
+	// A job publishes a handful of events per article, so this buffer is never filled by a live client
+	ch := make(chan NewsJobEvent, 32)
+
+	h.mu.Lock()
+	if h.subscribers[jobID] == nil {
+		h.subscribers[jobID] = make(map[chan NewsJobEvent]struct{})
+	}
+	h.subscribers[jobID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		delete(h.subscribers[jobID], ch)
+		if len(h.subscribers[jobID]) == 0 {
+			delete(h.subscribers, jobID)
+		}
+		h.mu.Unlock()
+	}
+}
+
+// publish sends an event to every listener of its job without blocking the generator
+func (h *newsEventHub) publish(event NewsJobEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subscribers[event.JobID] {
+		select {
+		case ch <- event:
+		default:
+			// The client is not reading; it can recover the state from GET /news/jobs/:id
+		}
+	}
+}
+
+// StreamNewsJob streams a generation job's progress as Server-Sent Events until the job finishes
+func StreamNewsJob(c echo.Context) error {
+	// Get user info from context
+	userID, _ := middleware.GetUserFromContext(c)
+	if userID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "User not authenticated",
+		})
+	}
+
+	jobID := c.Param("id")
+	if jobID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Job ID is required",
+		})
+	}
+
+	jobsCollection := mongodb.GetCollection(newsJobsCollection)
+	if jobsCollection == nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Database connection error",
+		})
+	}
+
+	// Subscribe before reading the job so no event falls between the snapshot and the stream
+	events, unsubscribe := jobEvents.subscribe(jobID)
+	defer unsubscribe()
+
+	var job model.NewsGenerationJob
+	err := jobsCollection.FindOne(context.Background(), bson.M{
+		"_id":     jobID,
+		"user_id": userID,
+	}).Decode(&job)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Job not found",
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Database error",
+		})
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set("Cache-Control", "no-cache")
+	res.Header().Set("Connection", "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no") // Stop nginx from buffering the stream
+	res.WriteHeader(http.StatusOK)
+
+	if err := writeSSE(res, NewsJobEvent{Type: NewsEventSnapshot, JobID: job.ID, Index: -1, Job: &job}); err != nil {
+		return nil
+	}
+	if job.IsFinished() {
+		return nil
+	}
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request().Context().Done():
+			return nil
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(res, ": ping\n\n"); err != nil {
+				return nil
+			}
+			res.Flush()
+		case event := <-events:
+			if err := writeSSE(res, event); err != nil {
+				return nil
+			}
+			if event.Type == NewsEventJobCompleted || event.Type == NewsEventJobFailed {
+				return nil
+			}
+		}
+	}
+}
+
+// writeSSE writes one event in the text/event-stream format and flushes it to the client
+func writeSSE(res *echo.Response, event NewsJobEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(res, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+		return err
+	}
+	res.Flush()
+	return nil
+}
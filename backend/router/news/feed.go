@@ -0,0 +1,319 @@
+package news
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"google-devjam-backend/model"
+	"google-devjam-backend/utils/mongodb"
+	"google-devjam-backend/utils/services"
+)
+
+// feedItemLimit is how many of the user's most recent articles a feed lists
+const feedItemLimit = 50
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomAuthor  `xml:"author"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Href   string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr,omitempty"`
+	Type   string `xml:"type,attr,omitempty"`
+	Length int    `xml:"length,attr,omitempty"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published"`
+	Links      []atomLink     `xml:"link"`
+	Categories []atomCategory `xml:"category"`
+	Summary    *atomText      `xml:"summary,omitempty"`
+	Content    atomText       `xml:"content"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type podcastRSS struct {
+	XMLName xml.Name       `xml:"rss"`
+	Version string         `xml:"version,attr"`
+	ITunes  string         `xml:"xmlns:itunes,attr"`
+	Channel podcastChannel `xml:"channel"`
+}
+
+type podcastChannel struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link,omitempty"`
+	Description string        `xml:"description"`
+	Language    string        `xml:"language"`
+	Author      string        `xml:"itunes:author"`
+	Explicit    string        `xml:"itunes:explicit"`
+	Category    podcastCat    `xml:"itunes:category"`
+	Items       []podcastItem `xml:"item"`
+}
+
+type podcastCat struct {
+	Text string `xml:"text,attr"`
+}
+
+type podcastItem struct {
+	Title       string           `xml:"title"`
+	Link        string           `xml:"link,omitempty"`
+	Description string           `xml:"description"`
+	GUID        podcastGUID      `xml:"guid"`
+	PubDate     string           `xml:"pubDate"`
+	Enclosure   podcastEnclosure `xml:"enclosure"`
+	Duration    string           `xml:"itunes:duration,omitempty"`
+}
+
+type podcastGUID struct {
+	IsPermaLink string `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type podcastEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length int    `xml:"length,attr"` // Unknown, which podcast apps accept as 0
+	Type   string `xml:"type,attr"`
+}
+
+// GetAtomFeed publishes the feed token owner's articles as an Atom feed
+func GetAtomFeed(c echo.Context) error {
+	userName, newsList, err := loadFeed(c.Param("token"), false)
+	if err != nil {
+		return feedError(c, err)
+	}
+
+	feedURL := c.Scheme() + "://" + c.Request().Host + c.Request().URL.Path
+	feed := atomFeed{
+		ID:      feedURL,
+		Title:   userName + "'s news",
+		Updated: feedUpdated(newsList).Format(time.RFC3339),
+		Author:  atomAuthor{Name: userName},
+		Links:   []atomLink{{Href: feedURL, Rel: "self", Type: "application/atom+xml"}},
+	}
+	if frontendURL := os.Getenv("FRONTEND_URL"); frontendURL != "" {
+		feed.Links = append(feed.Links, atomLink{Href: strings.TrimSuffix(frontendURL, "/") + "/news", Rel: "alternate", Type: "text/html"})
+	}
+
+	for _, news := range newsList {
+		entry := atomEntry{
+			ID:        "urn:devjam:news:" + news.ID,
+			Title:     news.Title,
+			Updated:   news.UpdatedAt.Format(time.RFC3339),
+			Published: news.CreatedAt.Format(time.RFC3339),
+			Content:   atomText{Type: "text", Body: news.Content},
+		}
+		if link := articleLink(news); link != "" {
+			entry.Links = append(entry.Links, atomLink{Href: link, Rel: "alternate", Type: "text/html"})
+		}
+		// Audio is optional in the Atom feed, so it is left out when it is not publicly reachable
+		if audioURL, ok := services.PublicAudioURL(news.AudioURL); ok {
+			entry.Links = append(entry.Links, atomLink{Href: audioURL, Rel: "enclosure", Type: "audio/wav"})
+		}
+		if news.Summary != "" {
+			entry.Summary = &atomText{Type: "text", Body: news.Summary}
+		}
+		for _, keyword := range news.Keywords {
+			entry.Categories = append(entry.Categories, atomCategory{Term: keyword})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	return writeFeed(c, "application/atom+xml; charset=utf-8", feed)
+}
+
+// GetPodcastFeed publishes the feed token owner's articles that have audio as a podcast RSS feed.
+// It needs PUBLIC_AUDIO_BASE_URL, since an episode whose audio players cannot download is useless.
+func GetPodcastFeed(c echo.Context) error {
+	if !services.PublicAudioConfigured() {
+		log.Printf("Warning: Podcast feed requested but PUBLIC_AUDIO_BASE_URL is not set, so audio is not publicly reachable")
+		return c.String(http.StatusServiceUnavailable, "Podcast feed is not available")
+	}
+
+	userName, newsList, err := loadFeed(c.Param("token"), true)
+	if err != nil {
+		return feedError(c, err)
+	}
+
+	channel := podcastChannel{
+		Title:       userName + "'s news",
+		Description: "Personalized English news articles, read aloud at " + userName + "'s level.",
+		Language:    "en",
+		Author:      userName,
+		Explicit:    "false",
+		Category:    podcastCat{Text: "Education"},
+	}
+	if frontendURL := os.Getenv("FRONTEND_URL"); frontendURL != "" {
+		channel.Link = strings.TrimSuffix(frontendURL, "/") + "/news"
+	}
+
+	for _, news := range newsList {
+		description := news.Summary
+		if description == "" {
+			description = excerpt(news.Content, 300)
+		}
+
+		audioURL, _ := services.PublicAudioURL(news.AudioURL)
+		item := podcastItem{
+			Title:       news.Title,
+			Link:        articleLink(news),
+			Description: description,
+			GUID:        podcastGUID{IsPermaLink: "false", Value: news.ID},
+			PubDate:     news.CreatedAt.Format(time.RFC1123Z),
+			Enclosure: podcastEnclosure{
+				URL:  audioURL,
+				Type: "audio/wav",
+			},
+		}
+		if n := len(news.AudioTimings); n > 0 {
+			seconds := int(news.AudioTimings[n-1].EndTime + 0.5)
+			item.Duration = fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+		}
+		channel.Items = append(channel.Items, item)
+	}
+
+	return writeFeed(c, "application/rss+xml; charset=utf-8", podcastRSS{
+		Version: "2.0",
+		ITunes:  "http://www.itunes.com/dtds/podcast-1.0.dtd",
+		Channel: channel,
+	})
+}
+
+// loadFeed returns the display name of the feed token's owner and their most recent articles, archived ones
+// included, newest first. withAudio limits the articles to those with audio.
+func loadFeed(token string, withAudio bool) (string, []model.News, error) {
+	userID, err := userIDForFeedToken(token)
+	if err != nil {
+		return "", nil, err
+	}
+
+	usersCollection := mongodb.GetCollection("users")
+	newsCollection := mongodb.GetCollection("news")
+	if usersCollection == nil || newsCollection == nil {
+		return "", nil, mongo.ErrClientDisconnected
+	}
+
+	var user model.User
+	if err := usersCollection.FindOne(context.Background(), bson.M{"_id": userID}).Decode(&user); err != nil {
+		return "", nil, err
+	}
+	userName := user.DisplayName
+	if userName == "" {
+		userName = "Reader"
+	}
+
+	filter := bson.M{"user_id": userID}
+	if withAudio {
+		filter["audio_url"] = bson.M{"$exists": true, "$ne": ""}
+	}
+
+	cursor, err := newsCollection.Find(
+		context.Background(),
+		filter,
+		options.Find().
+			SetSort(bson.D{{Key: "created_at", Value: -1}}).
+			SetLimit(feedItemLimit).
+			SetProjection(bson.M{
+				"highlight_spans": 0,
+				"questions":       0,
+				"gloss":           0,
+				"sources":         0,
+			}),
+	)
+	if err != nil {
+		return "", nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	var newsList []model.News
+	if err := cursor.All(context.Background(), &newsList); err != nil {
+		return "", nil, err
+	}
+	newsList = cleanNewsAudioURLs(newsList)
+
+	return userName, newsList, nil
+}
+
+// feedError maps a loadFeed error to a response; feed readers only need to tell a bad link from an outage
+func feedError(c echo.Context, err error) error {
+	if err == mongo.ErrNoDocuments {
+		return c.String(http.StatusNotFound, "Feed not found")
+	}
+	return c.String(http.StatusInternalServerError, "Failed to load feed")
+}
+
+// writeFeed renders a feed document as XML
+func writeFeed(c echo.Context, contentType string, feed interface{}) error {
+	body, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to render feed")
+	}
+	return c.Blob(http.StatusOK, contentType, append([]byte(xml.Header), body...))
+}
+
+// feedUpdated returns when the newest article changed, or now for an empty feed
+func feedUpdated(newsList []model.News) time.Time {
+	updated := time.Time{}
+	for _, news := range newsList {
+		if news.UpdatedAt.After(updated) {
+			updated = news.UpdatedAt
+		}
+	}
+	if updated.IsZero() {
+		return time.Now()
+	}
+	return updated
+}
+
+// articleLink returns the article's page in the app, or "" if FRONTEND_URL is not set
+func articleLink(news model.News) string {
+	frontendURL := os.Getenv("FRONTEND_URL")
+	if frontendURL == "" {
+		return ""
+	}
+	return strings.TrimSuffix(frontendURL, "/") + "/news/" + news.ID
+}
+
+// excerpt returns the start of text, cut at a word boundary, with an ellipsis if anything was cut
+func excerpt(text string, maxRunes int) string {
+	runes := []rune(strings.Join(strings.Fields(text), " "))
+	if len(runes) <= maxRunes {
+		return string(runes)
+	}
+	cut := string(runes[:maxRunes])
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	}
+	return cut + "…"
+}
@@ -0,0 +1,189 @@
+package news
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"google-devjam-backend/model"
+	"google-devjam-backend/utils/encrypt"
+	"google-devjam-backend/utils/middleware"
+	"google-devjam-backend/utils/mongodb"
+	"google-devjam-backend/utils/services"
+)
+
+const feedTokensCollection = "feed_tokens"
+
+type FeedTokenResponse struct {
+	FeedToken  *model.FeedToken `json:"feed_token"`            // Nil when the user has no token
+	Token      string           `json:"token,omitempty"`       // Only returned when the token is created
+	AtomURL    string           `json:"atom_url,omitempty"`    // Only returned when the token is created
+	PodcastURL string           `json:"podcast_url,omitempty"` // Only returned when the token is created and audio is publicly reachable
+}
+
+// GetFeedToken reports whether the user has a feed token and when it was created and last used
+func GetFeedToken(c echo.Context) error {
+	// Get user info from context
+	userID, _ := middleware.GetUserFromContext(c)
+	if userID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "User not authenticated",
+		})
+	}
+
+	tokensCollection := mongodb.GetCollection(feedTokensCollection)
+	if tokensCollection == nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Database connection error",
+		})
+	}
+
+	var feedToken model.FeedToken
+	err := tokensCollection.FindOne(context.Background(), bson.M{"user_id": userID}).Decode(&feedToken)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.JSON(http.StatusOK, FeedTokenResponse{})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Database error",
+		})
+	}
+
+	return c.JSON(http.StatusOK, FeedTokenResponse{
+		FeedToken: &feedToken,
+	})
+}
+
+// CreateFeedToken creates a new feed token for the user, revoking the previous one, and returns the feed URLs
+func CreateFeedToken(c echo.Context) error {
+	// Get user info from context
+	userID, _ := middleware.GetUserFromContext(c)
+	if userID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "User not authenticated",
+		})
+	}
+
+	tokensCollection := mongodb.GetCollection(feedTokensCollection)
+	if tokensCollection == nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Database connection error",
+		})
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to generate feed token",
+		})
+	}
+	token := base64.RawURLEncoding.EncodeToString(secret)
+
+	tokenID, err := encrypt.GenerateSnowflakeID()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to generate feed token",
+		})
+	}
+
+	// Overwriting the hash in the user's one token document revokes the old token immediately. A single upsert
+	// leaves no moment in which the user has no token, and the unique user_id index stops a second document.
+	var feedToken model.FeedToken
+	err = tokensCollection.FindOneAndUpdate(
+		context.Background(),
+		bson.M{"user_id": userID},
+		bson.M{
+			"$set":         bson.M{"token_hash": hashFeedToken(token), "created_at": time.Now()},
+			"$unset":       bson.M{"last_used_at": ""},
+			"$setOnInsert": bson.M{"_id": tokenID},
+		},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&feedToken)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to save feed token",
+		})
+	}
+
+	baseURL := c.Scheme() + "://" + c.Request().Host
+	response := FeedTokenResponse{
+		FeedToken: &feedToken,
+		Token:     token,
+		AtomURL:   baseURL + "/feeds/" + token + "/atom.xml",
+	}
+	if services.PublicAudioConfigured() {
+		response.PodcastURL = baseURL + "/feeds/" + token + "/podcast.xml"
+	}
+	return c.JSON(http.StatusCreated, response)
+}
+
+// RevokeFeedToken deletes the user's feed token, so their feed URLs stop working
+func RevokeFeedToken(c echo.Context) error {
+	// Get user info from context
+	userID, _ := middleware.GetUserFromContext(c)
+	if userID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "User not authenticated",
+		})
+	}
+
+	tokensCollection := mongodb.GetCollection(feedTokensCollection)
+	if tokensCollection == nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Database connection error",
+		})
+	}
+
+	result, err := tokensCollection.DeleteOne(context.Background(), bson.M{"user_id": userID})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to revoke feed token",
+		})
+	}
+	if result.DeletedCount == 0 {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "No feed token to revoke",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "Feed token revoked",
+	})
+}
+
+// userIDForFeedToken returns the user a feed token belongs to and records that it was used.
+// It returns mongo.ErrNoDocuments for unknown or revoked tokens.
+func userIDForFeedToken(token string) (string, error) {
+	tokensCollection := mongodb.GetCollection(feedTokensCollection)
+	if tokensCollection == nil {
+		return "", mongo.ErrClientDisconnected
+	}
+
+	var feedToken model.FeedToken
+	err := tokensCollection.FindOneAndUpdate(
+		context.Background(),
+		bson.M{"token_hash": hashFeedToken(token)},
+		bson.M{"$set": bson.M{"last_used_at": time.Now()}},
+		options.FindOneAndUpdate().SetProjection(bson.M{"user_id": 1}),
+	).Decode(&feedToken)
+	if err != nil {
+		return "", err
+	}
+
+	return feedToken.UserID, nil
+}
+
+// hashFeedToken returns the stored form of a feed token
+func hashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
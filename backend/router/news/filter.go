@@ -0,0 +1,287 @@
+package news
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"google-devjam-backend/model"
+	"google-devjam-backend/utils/mongodb"
+)
+
+const (
+	newsSortDate  = "date"
+	newsSortLevel = "level"
+)
+
+// newsCursor marks the last article of a page; the next page starts right after it in the requested order
+type newsCursor struct {
+	Level     int       `json:"l"`
+	CreatedAt time.Time `json:"c"`
+	ID        string    `json:"i"`
+}
+
+// newsListQuery is a parsed GET /news query
+type newsListQuery struct {
+	filter     bson.M
+	sortBy     string
+	descending bool
+	limit      int
+	cursor     *newsCursor
+}
+
+// parseNewsListQuery builds the filter, sort and page of GET /news from the query string.
+// Errors are meant to be returned to the client as they are.
+func parseNewsListQuery(c echo.Context, userID string) (*newsListQuery, error) {
+	query := &newsListQuery{
+		filter:     bson.M{"user_id": userID},
+		sortBy:     newsSortDate,
+		descending: true,
+		limit:      10,
+	}
+	filter := query.filter
+
+	// Page numbers were replaced by cursors; ignoring page would silently return the first page every time
+	if c.QueryParam("page") != "" {
+		return nil, fmt.Errorf("page is no longer supported, pass next_cursor from the previous response as cursor")
+	}
+
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil || l <= 0 || l > 50 {
+			return nil, fmt.Errorf("limit must be between 1 and 50")
+		}
+		query.limit = l
+	}
+
+	// Archived articles form the user's history and are hidden unless asked for ("true" or "all")
+	switch c.QueryParam("archived") {
+	case "true":
+		filter["archived_at"] = bson.M{"$exists": true}
+	case "all":
+		// No archive filter
+	default:
+		filter["archived_at"] = bson.M{"$exists": false}
+	}
+
+	// Add favorite and read state filters if provided
+	if exists, ok := parseStateFilter(c.QueryParam("favorite")); ok {
+		filter["favorited_at"] = bson.M{"$exists": exists}
+	}
+	if exists, ok := parseStateFilter(c.QueryParam("read")); ok {
+		filter["read_at"] = bson.M{"$exists": exists}
+	}
+	if hasAudio, ok := parseStateFilter(c.QueryParam("has_audio")); ok {
+		if hasAudio {
+			filter["audio_url"] = bson.M{"$exists": true, "$ne": ""}
+		} else {
+			filter["audio_url"] = bson.M{"$in": []interface{}{nil, ""}} // Matches missing fields too
+		}
+	}
+
+	// Level filters: an exact level, or a range with min_level and max_level
+	levelFilter := bson.M{}
+	for param, op := range map[string]string{"level": "$eq", "min_level": "$gte", "max_level": "$lte"} {
+		value := c.QueryParam(param)
+		if value == "" {
+			continue
+		}
+		level, err := strconv.Atoi(value)
+		if err != nil || level < 1 || level > 10 {
+			return nil, fmt.Errorf("%s must be a level from 1 to 10", param)
+		}
+		levelFilter[op] = level
+	}
+	if len(levelFilter) > 0 {
+		filter["level"] = levelFilter
+	}
+
+	// Creation date range; a date without a time covers that whole day
+	createdFilter := bson.M{}
+	if from := c.QueryParam("from"); from != "" {
+		t, _, err := parseDateParam(from)
+		if err != nil {
+			return nil, fmt.Errorf("from must be a date (YYYY-MM-DD) or an RFC 3339 time")
+		}
+		createdFilter["$gte"] = t
+	}
+	if to := c.QueryParam("to"); to != "" {
+		t, dateOnly, err := parseDateParam(to)
+		if err != nil {
+			return nil, fmt.Errorf("to must be a date (YYYY-MM-DD) or an RFC 3339 time")
+		}
+		if dateOnly {
+			createdFilter["$lt"] = t.AddDate(0, 0, 1)
+		} else {
+			createdFilter["$lte"] = t
+		}
+	}
+	if len(createdFilter) > 0 {
+		filter["created_at"] = createdFilter
+	}
+
+	// Keyword filter matches a whole keyword, ignoring case
+	if keyword := c.QueryParam("keyword"); keyword != "" {
+		filter["keywords"] = bson.M{"$regex": "^" + regexp.QuoteMeta(keyword) + "$", "$options": "i"}
+	}
+
+	// Search matches the text literally, so characters like "(" or "+" in a query are not regex syntax
+	if searchQuery := c.QueryParam("search"); searchQuery != "" {
+		pattern := regexp.QuoteMeta(searchQuery)
+		filter["$or"] = []bson.M{
+			{"title": bson.M{"$regex": pattern, "$options": "i"}},
+			{"content": bson.M{"$regex": pattern, "$options": "i"}},
+			{"keywords": bson.M{"$regex": "^" + pattern + "$", "$options": "i"}},
+		}
+	}
+
+	switch c.QueryParam("sort") {
+	case "", newsSortDate:
+		query.sortBy = newsSortDate
+	case newsSortLevel:
+		query.sortBy = newsSortLevel
+	default:
+		return nil, fmt.Errorf("sort must be \"date\" or \"level\"")
+	}
+
+	switch c.QueryParam("order") {
+	case "", "desc":
+		query.descending = true
+	case "asc":
+		query.descending = false
+	default:
+		return nil, fmt.Errorf("order must be \"asc\" or \"desc\"")
+	}
+
+	if cursorStr := c.QueryParam("cursor"); cursorStr != "" {
+		cursor, err := decodeNewsCursor(cursorStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor")
+		}
+		query.cursor = cursor
+	}
+
+	return query, nil
+}
+
+// sortKeys returns the fields articles are ordered by; _id comes last so the order is total
+func (q *newsListQuery) sortKeys() []string {
+	if q.sortBy == newsSortLevel {
+		return []string{"level", "created_at", "_id"}
+	}
+	return []string{"created_at", "_id"}
+}
+
+// sort returns the sort document for the query
+func (q *newsListQuery) sort() bson.D {
+	direction := 1
+	if q.descending {
+		direction = -1
+	}
+
+	var sort bson.D
+	for _, key := range q.sortKeys() {
+		sort = append(sort, bson.E{Key: key, Value: direction})
+	}
+	return sort
+}
+
+// pageFilter returns the filter for the requested page: the query's filter, plus the articles after the cursor
+func (q *newsListQuery) pageFilter() bson.M {
+	if q.cursor == nil {
+		return q.filter
+	}
+
+	values := map[string]interface{}{
+		"level":      q.cursor.Level,
+		"created_at": q.cursor.CreatedAt,
+		"_id":        q.cursor.ID,
+	}
+	op := "$gt"
+	if q.descending {
+		op = "$lt"
+	}
+
+	// Keyset condition: (a > x) or (a == x and b > y) or (a == x and b == y and c > z)
+	keys := q.sortKeys()
+	after := make([]bson.M, len(keys))
+	for i, key := range keys {
+		condition := bson.M{key: bson.M{op: values[key]}}
+		for _, prev := range keys[:i] {
+			condition[prev] = values[prev]
+		}
+		after[i] = condition
+	}
+
+	return bson.M{"$and": []bson.M{q.filter, {"$or": after}}}
+}
+
+// encodeNewsCursor returns the cursor of the page that starts after news
+func encodeNewsCursor(news model.News) string {
+	data, _ := json.Marshal(newsCursor{Level: news.Level, CreatedAt: news.CreatedAt, ID: news.ID})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeNewsCursor parses a cursor returned by encodeNewsCursor
+func decodeNewsCursor(value string) (*newsCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor newsCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, err
+	}
+	if cursor.ID == "" {
+		return nil, fmt.Errorf("cursor has no article ID")
+	}
+	return &cursor, nil
+}
+
+// parseDateParam parses a YYYY-MM-DD date (as UTC midnight) or an RFC 3339 time
+func parseDateParam(value string) (t time.Time, dateOnly bool, err error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, true, nil
+	}
+	t, err = time.Parse(time.RFC3339, value)
+	return t, false, err
+}
+
+// parseStateFilter parses a "true"/"false" query value; ok is false when the filter is not given
+func parseStateFilter(value string) (exists bool, ok bool) {
+	switch value {
+	case "true":
+		return true, true
+	case "false":
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+// NormalizeNewsLevels converts levels that older articles stored as strings to ints, so the level filters and sort
+// see every article. Unparseable levels become 1, matching how model.News decodes them.
+func NormalizeNewsLevels() error {
+	newsCollection := mongodb.GetCollection("news")
+	if newsCollection == nil {
+		return mongo.ErrClientDisconnected
+	}
+
+	_, err := newsCollection.UpdateMany(
+		context.Background(),
+		bson.M{"level": bson.M{"$type": "string"}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{
+			"level": bson.M{"$convert": bson.M{"input": "$level", "to": "int", "onError": 1, "onNull": 1}},
+		}}}},
+	)
+	return err
+}
@@ -5,7 +5,6 @@ import (
 	"log"
 	"net/http"
 	"net/url"
-	"strconv"
 	"strings"
 	"time"
 
@@ -16,20 +15,21 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"google-devjam-backend/model"
-	"google-devjam-backend/utils/encrypt"
-	"google-devjam-backend/utils/gemini"
+	"google-devjam-backend/router/vocabulary"
 	"google-devjam-backend/utils/middleware"
 	"google-devjam-backend/utils/mongodb"
-	"google-devjam-backend/utils/services"
 )
 
 type GenerateNewsResponse struct {
-	AllNews []model.News `json:"all_news"`
+	AllNews []model.News             `json:"all_news"`
+	Job     *model.NewsGenerationJob `json:"job,omitempty"` // Set when new articles are being generated
 }
 
-// GenerateNews generates personalized news based on user preferences and vocabulary
+// GenerateNews starts generating personalized news based on user preferences and vocabulary
 // If user has less than 4 news, generates enough to reach 4 total
-// If user has 4+ news, generates 4 new articles every 4 hours
+// If user has 4+ news, generates 4 new articles every batch interval (4 hours by default)
+// Fitting articles from the shared pool are served first; Gemini is only called for the rest
+// Generation runs as a background job; the response returns the existing news and the job to poll
 func GenerateNews(c echo.Context) error {
 	// Get user info from context
 	userID, _ := middleware.GetUserFromContext(c)
@@ -39,158 +39,98 @@ func GenerateNews(c echo.Context) error {
 		})
 	}
 
-	// Step 1: Get all existing user news
-	allNews, err := getAllUserNews(userID)
+	// Hold the user's lock so a scheduled batch cannot start between planning and creating the job
+	unlock := lockUser(userID)
+
+	// A job in progress is already filling the batch, so the pool must not be drawn from for it again
+	active, err := getActiveNewsJob(userID)
 	if err != nil {
+		unlock()
 		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to get user news: " + err.Error(),
+			"error": "Failed to check user news: " + err.Error(),
 		})
 	}
 
-	// Step 2: Determine how many news to generate
-	var newsToGenerate int
-	if len(allNews) < 4 {
-		// Generate enough news to reach 4 total
-		newsToGenerate = 4 - len(allNews)
-	} else {
-		// Check if user has recent news (within 4 hours)
-		recentNews, err := getRecentUserNews(userID)
-		if err != nil {
-			return c.JSON(http.StatusInternalServerError, map[string]string{
-				"error": "Failed to check recent news: " + err.Error(),
-			})
-		}
-
-		// If recent news exists, return existing news without generating new ones
-		if recentNews != nil {
-			return c.JSON(http.StatusOK, GenerateNewsResponse{
-				AllNews: allNews,
-			})
-		}
+	// Step 1: Determine how many news to generate
+	newsToGenerate, allNews, err := planNewsBatch(userID)
+	if err != nil {
+		unlock()
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to check user news: " + err.Error(),
+		})
+	}
 
-		// Generate 4 new articles since no recent news found (every 4 hours)
-		newsToGenerate = 4
+	if active != nil {
+		unlock()
+		return c.JSON(http.StatusAccepted, GenerateNewsResponse{
+			AllNews: allNews,
+			Job:     active,
+		})
 	}
 
-	// Step 3: Get user preferences
-	userPreferences, err := getUserPreferences(userID)
-	if err != nil {
-		// Continue without preferences if not found, but log the error
-		userPreferences = nil
+	// If recent news exists, return existing news without generating new ones
+	if newsToGenerate == 0 {
+		unlock()
+		return c.JSON(http.StatusOK, GenerateNewsResponse{
+			AllNews: allNews,
+		})
 	}
 
-	// Step 4: Get user's vocabulary words for learning and review
-	learnWords, reviewWords, err := getUserVocabularyForNews(userID)
+	// Step 2: Serve what the shared pool can right away
+	pooled, err := servePooledNews(userID, newsToGenerate)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to get user vocabulary: " + err.Error(),
+		log.Printf("Warning: Failed to serve pooled news to user %s: %v", userID, err)
+	}
+	allNews = append(pooled, allNews...) // Newest first
+	newsToGenerate -= len(pooled)
+	if newsToGenerate <= 0 {
+		unlock()
+		return c.JSON(http.StatusOK, GenerateNewsResponse{
+			AllNews: allNews,
 		})
 	}
 
-	// Step 5: Generate the required number of news
-	newsCollection := mongodb.GetCollection("news")
-	if newsCollection == nil {
+	// Step 3: Generate the rest in the background and let the client poll the job
+	job, created, err := createNewsJob(userID, newsToGenerate, false, false)
+	unlock()
+	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Database connection error",
+			"error": "Failed to start news generation: " + err.Error(),
 		})
 	}
+	if created {
+		go runNewsJob(*job)
+	}
 
-	for i := 0; i < newsToGenerate; i++ {
-		// Get existing titles to avoid duplicate topics
-		existingTitles := make([]string, len(allNews))
-		for j, news := range allNews {
-			existingTitles[j] = news.Title
-		}
-
-		// Generate news using Gemini
-		newsReq := gemini.NewsGenerationRequest{
-			UserPreferences: userPreferences,
-			LearnWords:      learnWords,
-			ReviewWords:     reviewWords,
-			ExistingTitles:  existingTitles,
-		}
-
-		newsResult, err := gemini.GeneratePersonalizedNews(newsReq)
-		if err != nil {
-			return c.JSON(http.StatusInternalServerError, map[string]string{
-				"error": "Failed to generate news: " + err.Error(),
-			})
-		}
-
-		// Generate news ID
-		newsID, err := encrypt.GenerateSnowflakeID()
-		if err != nil {
-			return c.JSON(http.StatusInternalServerError, map[string]string{
-				"error": "Failed to generate news ID",
-			})
-		}
-
-		// Combine learning and review words that were sent to Gemini
-		allVocabWords := append(learnWords, reviewWords...)
-
-		// Convert level from string to int
-		level, err := strconv.Atoi(newsResult.Level)
-		if err != nil {
-			log.Printf("Warning: Failed to parse level '%s', defaulting to 1: %v", newsResult.Level, err)
-			level = 1 // Default to level 1 if parsing fails
-		}
-
-		now := time.Now()
-		news := model.News{
-			ID:         newsID,
-			UserID:     userID,
-			Title:      newsResult.Title,
-			Content:    newsResult.Content,
-			Level:      level,
-			Keywords:   newsResult.Keywords,
-			WordInNews: allVocabWords, // Use the words we sent to Gemini, not what Gemini returns
-			Source:     newsResult.Source,
-			CreatedAt:  now,
-			UpdatedAt:  now,
-		}
-
-		// Generate and store audio for the news content
-		audioService, err := services.NewAudioService()
-		if err != nil {
-			log.Printf("Warning: Failed to initialize audio service: %v", err)
-			// Continue without audio generation
-		} else {
-			// Generate audio from the news content
-			audioURL, audioKey, err := audioService.GenerateAndStoreAudio(newsResult.Content, newsID)
-			if err != nil {
-				log.Printf("Warning: Failed to generate audio for news %s: %v", newsID, err)
-				// Continue without audio - don't fail the entire operation
-			} else {
-				// Add audio information to the news
-				news.AudioURL = audioURL
-				news.AudioKey = audioKey
-				log.Printf("Audio generated successfully for news %s: %s", newsID, audioURL)
-			}
-		}
+	return c.JSON(http.StatusAccepted, GenerateNewsResponse{
+		AllNews: allNews,
+		Job:     job,
+	})
+}
 
-		// Insert news into database
-		_, err = newsCollection.InsertOne(context.Background(), news)
-		if err != nil {
-			return c.JSON(http.StatusInternalServerError, map[string]string{
-				"error": "Failed to store news: " + err.Error(),
-			})
-		}
+// planNewsBatch returns how many articles the user needs now, along with their existing news.
+// Users with fewer than 4 articles are topped up to 4; otherwise 4 new articles are due once the batch interval has passed.
+func planNewsBatch(userID string) (int, []model.News, error) {
+	allNews, err := getAllUserNews(userID)
+	if err != nil {
+		return 0, nil, err
+	}
 
-		// Add the newly generated news to allNews for the next iteration
-		allNews = append([]model.News{news}, allNews...)
+	if len(allNews) < 4 {
+		// Generate enough news to reach 4 total
+		return 4 - len(allNews), allNews, nil
 	}
 
-	// Get all user news including the newly generated ones
-	updatedAllNews, err := getAllUserNews(userID)
+	recentNews, err := getRecentUserNews(userID)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to get all user news: " + err.Error(),
-		})
+		return 0, nil, err
+	}
+	if recentNews != nil {
+		return 0, allNews, nil
 	}
 
-	return c.JSON(http.StatusCreated, GenerateNewsResponse{
-		AllNews: updatedAllNews,
-	})
+	// Generate 4 new articles since no recent news found
+	return 4, allNews, nil
 }
 
 // getUserPreferences retrieves user preferences
@@ -280,30 +220,12 @@ func getUserVocabularyForNews(userID string) (learnWords []string, reviewWords [
 			continue
 		}
 
-		// Implement forgetting curve logic
-		daysSinceUpdate := int(now.Sub(updatedAt).Hours() / 24)
-
-		// Words that are new or have low fluency are for learning
-		if learnCount < 3 || fluency < 50 {
+		// Words that are new or have low fluency are for learning;
+		// the rest need review once their forgetting-curve interval has passed
+		if vocabulary.NeedsLearning(int(learnCount), int(fluency)) {
 			learnWords = append(learnWords, word)
-		} else {
-			// Words that need review based on forgetting curve
-			// The higher the fluency, the longer the interval before review
-			var reviewInterval int
-			switch {
-			case fluency >= 90:
-				reviewInterval = 30 // Review after 30 days for high fluency
-			case fluency >= 70:
-				reviewInterval = 14 // Review after 14 days for medium-high fluency
-			case fluency >= 50:
-				reviewInterval = 7 // Review after 7 days for medium fluency
-			default:
-				reviewInterval = 3 // Review after 3 days for low fluency
-			}
-
-			if daysSinceUpdate >= reviewInterval {
-				reviewWords = append(reviewWords, word)
-			}
+		} else if vocabulary.IsDueForReview(int(fluency), updatedAt, now) {
+			reviewWords = append(reviewWords, word)
 		}
 	}
 
@@ -352,17 +274,20 @@ func cleanNewsAudioURLs(newsList []model.News) []model.News {
 	return newsList
 }
 
-// getAllUserNews finds all the news for this specific user
+// getAllUserNews finds all the current news for this specific user, excluding archived articles
 func getAllUserNews(userID string) ([]model.News, error) {
 	newsCollection := mongodb.GetCollection("news")
 	if newsCollection == nil {
 		return nil, mongo.ErrClientDisconnected
 	}
 
-	// Find all the news for this specific user
+	// Find all the current (not archived) news for this specific user
 	cursor, err := newsCollection.Find(
 		context.Background(),
-		bson.M{"user_id": userID},
+		bson.M{
+			"user_id":     userID,
+			"archived_at": bson.M{"$exists": false},
+		},
 		&options.FindOptions{
 			Sort: bson.D{{Key: "created_at", Value: -1}}, // Get the most recent first
 		},
@@ -384,23 +309,23 @@ func getAllUserNews(userID string) ([]model.News, error) {
 	return newsList, nil
 }
 
-// getRecentUserNews checks if user has news generated within the last 4 hours
+// getRecentUserNews checks if user has news generated within the last batch interval
 func getRecentUserNews(userID string) (*model.News, error) {
 	newsCollection := mongodb.GetCollection("news")
 	if newsCollection == nil {
 		return nil, mongo.ErrClientDisconnected
 	}
 
-	// Calculate 4 hours ago
-	fourHoursAgo := time.Now().Add(-4 * time.Hour)
+	batchStart := time.Now().Add(-newsBatchInterval())
 
 	var news model.News
 	err := newsCollection.FindOne(
 		context.Background(),
 		bson.M{
-			"user_id": userID,
+			"user_id":     userID,
+			"archived_at": bson.M{"$exists": false},
 			"created_at": bson.M{
-				"$gte": fourHoursAgo,
+				"$gte": batchStart,
 			},
 		},
 		&options.FindOneOptions{
@@ -421,7 +346,9 @@ func getRecentUserNews(userID string) (*model.News, error) {
 	return &news, nil
 }
 
-// ForceGenerateNews generates 4 new personalized news articles regardless of existing news
+// ForceGenerateNews replaces the user's news with 4 newly generated articles; the old ones are archived, not deleted,
+// once the new ones are stored.
+// Generation runs as a background job whose progress is available at GET /news/jobs/:id.
 func ForceGenerateNews(c echo.Context) error {
 	// Get user info from context
 	userID, _ := middleware.GetUserFromContext(c)
@@ -431,133 +358,14 @@ func ForceGenerateNews(c echo.Context) error {
 		})
 	}
 
-	// Step 1: Delete all existing user news
-	newsCollection := mongodb.GetCollection("news")
-	if newsCollection == nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Database connection error",
-		})
-	}
-
-	_, err := newsCollection.DeleteMany(context.Background(), bson.M{"user_id": userID})
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to delete existing news: " + err.Error(),
-		})
-	}
-
-	// Step 2: Get user preferences
-	userPreferences, err := getUserPreferences(userID)
-	if err != nil {
-		// Continue without preferences if not found, but log the error
-		userPreferences = nil
-	}
-
-	// Step 3: Get user's vocabulary words for learning and review
-	learnWords, reviewWords, err := getUserVocabularyForNews(userID)
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to get user vocabulary: " + err.Error(),
-		})
-	}
-
-// Step 4: Generate 4 new articles
-var newlyGeneratedNews []model.News
-
-for i := 0; i < 4; i++ {
-// Get existing titles to avoid duplicate topics (only from newly generated news in this batch)
-var existingTitles []string
-for _, news := range newlyGeneratedNews {
-existingTitles = append(existingTitles, news.Title)
-}
-
-		// Generate news using Gemini
-		newsReq := gemini.NewsGenerationRequest{
-			UserPreferences: userPreferences,
-			LearnWords:      learnWords,
-			ReviewWords:     reviewWords,
-			ExistingTitles:  existingTitles,
-		}
-
-		newsResult, err := gemini.GeneratePersonalizedNews(newsReq)
-		if err != nil {
-			return c.JSON(http.StatusInternalServerError, map[string]string{
-				"error": "Failed to generate news: " + err.Error(),
-			})
-		}
-
-		// Generate news ID
-		newsID, err := encrypt.GenerateSnowflakeID()
-		if err != nil {
-			return c.JSON(http.StatusInternalServerError, map[string]string{
-				"error": "Failed to generate news ID",
-			})
-		}
-
-		// Combine learning and review words that were sent to Gemini
-		allVocabWords := append(learnWords, reviewWords...)
-
-		// Convert level from string to int
-		level, err := strconv.Atoi(newsResult.Level)
-		if err != nil {
-			log.Printf("Warning: Failed to parse level '%s', defaulting to 1: %v", newsResult.Level, err)
-			level = 1 // Default to level 1 if parsing fails
-		}
-
-		now := time.Now()
-		news := model.News{
-			ID:         newsID,
-			UserID:     userID,
-			Title:      newsResult.Title,
-			Content:    newsResult.Content,
-			Level:      level,
-			Keywords:   newsResult.Keywords,
-			WordInNews: allVocabWords, // Use the words we sent to Gemini, not what Gemini returns
-			Source:     newsResult.Source,
-			CreatedAt:  now,
-			UpdatedAt:  now,
-		}
-
-		// Generate and store audio for the news content
-		audioService, err := services.NewAudioService()
-		if err != nil {
-			log.Printf("Warning: Failed to initialize audio service: %v", err)
-			// Continue without audio generation
-		} else {
-			// Generate audio from the news content
-			audioURL, audioKey, err := audioService.GenerateAndStoreAudio(newsResult.Content, newsID)
-			if err != nil {
-				log.Printf("Warning: Failed to generate audio for news %s: %v", newsID, err)
-				// Continue without audio - don't fail the entire operation
-			} else {
-				// Add audio information to the news
-				news.AudioURL = audioURL
-				news.AudioKey = audioKey
-				log.Printf("Audio generated successfully for news %s: %s", newsID, audioURL)
-			}
-		}
-
-		// Insert news into database
-		_, err = newsCollection.InsertOne(context.Background(), news)
-		if err != nil {
-			return c.JSON(http.StatusInternalServerError, map[string]string{
-				"error": "Failed to store news: " + err.Error(),
-			})
-		}
-
-		// Add to newly generated news list
-		newlyGeneratedNews = append(newlyGeneratedNews, news)
-	}
-
-	// Get all user news including the newly generated ones
-	updatedAllNews, err := getAllUserNews(userID)
+	job, _, err := startNewsJob(userID, 4, true)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to get all user news: " + err.Error(),
+			"error": "Failed to start news generation: " + err.Error(),
 		})
 	}
 
-	return c.JSON(http.StatusCreated, GenerateNewsResponse{
-		AllNews: updatedAllNews,
+	return c.JSON(http.StatusAccepted, NewsJobResponse{
+		Job: *job,
 	})
 }
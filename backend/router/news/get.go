@@ -2,10 +2,11 @@ package news
 
 import (
 	"context"
+	"log"
 	"net/http"
 	"net/url"
-	"strconv"
 	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"go.mongodb.org/mongo-driver/bson"
@@ -14,13 +15,14 @@ import (
 	"google-devjam-backend/model"
 	"google-devjam-backend/utils/middleware"
 	"google-devjam-backend/utils/mongodb"
+	"google-devjam-backend/utils/nlp"
 )
 
 type GetNewsResponse struct {
-	News  []model.News `json:"news"`
-	Total int64        `json:"total"`
-	Page  int          `json:"page"`
-	Limit int          `json:"limit"`
+	News       []model.News `json:"news"`
+	Total      int64        `json:"total"` // Articles matching the filters, across all pages
+	Limit      int          `json:"limit"`
+	NextCursor string       `json:"next_cursor,omitempty"` // Pass as cursor to get the next page; empty on the last page
 }
 
 type GetSingleNewsResponse struct {
@@ -53,7 +55,7 @@ func cleanAudioURLInGet(audioURL string) string {
 	return audioURL
 }
 
-// GetNews retrieves news articles with pagination and filtering
+// GetNews retrieves news articles with filtering, sorting and cursor pagination
 func GetNews(c echo.Context) error {
 	// Get user info from context
 	userID, _ := middleware.GetUserFromContext(c)
@@ -63,28 +65,13 @@ func GetNews(c echo.Context) error {
 		})
 	}
 
-	// Parse pagination parameters
-	page := 1
-	limit := 10
-
-	if pageStr := c.QueryParam("page"); pageStr != "" {
-		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
-			page = p
-		}
-	}
-
-	if limitStr := c.QueryParam("limit"); limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 50 {
-			limit = l
-		}
+	query, err := parseNewsListQuery(c, userID)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
 	}
 
-	// Parse level filter
-	level := c.QueryParam("level")
-
-	// Parse search query
-	searchQuery := c.QueryParam("search")
-
 	// Get news collection
 	newsCollection := mongodb.GetCollection("news")
 	if newsCollection == nil {
@@ -93,43 +80,20 @@ func GetNews(c echo.Context) error {
 		})
 	}
 
-	// Build filter - only show news for this user
-	filter := bson.M{
-		"user_id": userID,
-	}
-
-	// Add level filter if provided
-	if level != "" {
-		filter["level"] = level
-	}
-
-	// Add search filter if provided
-	if searchQuery != "" {
-		filter["$or"] = []bson.M{
-			{"title": bson.M{"$regex": searchQuery, "$options": "i"}},
-			{"content": bson.M{"$regex": searchQuery, "$options": "i"}},
-			{"keywords": bson.M{"$in": []string{searchQuery}}},
-		}
-	}
-
 	// Count total documents
-	total, err := newsCollection.CountDocuments(context.Background(), filter)
+	total, err := newsCollection.CountDocuments(context.Background(), query.filter)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": "Database error",
 		})
 	}
 
-	// Calculate skip
-	skip := (page - 1) * limit
-
-	// Find documents with pagination
+	// Fetch one extra article to know whether there is a next page
 	findOptions := options.Find()
-	findOptions.SetSort(bson.D{{Key: "created_at", Value: -1}}) // Sort by newest first
-	findOptions.SetSkip(int64(skip))
-	findOptions.SetLimit(int64(limit))
+	findOptions.SetSort(query.sort())
+	findOptions.SetLimit(int64(query.limit + 1))
 
-	cursor, err := newsCollection.Find(context.Background(), filter, findOptions)
+	cursor, err := newsCollection.Find(context.Background(), query.pageFilter(), findOptions)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": "Database error",
@@ -137,23 +101,29 @@ func GetNews(c echo.Context) error {
 	}
 	defer cursor.Close(context.Background())
 
-	var news []model.News
+	news := []model.News{}
 	if err := cursor.All(context.Background(), &news); err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": "Database error",
 		})
 	}
 
+	nextCursor := ""
+	if len(news) > query.limit {
+		news = news[:query.limit]
+		nextCursor = encodeNewsCursor(news[len(news)-1])
+	}
+
 	// Clean audio URLs to ensure they only contain paths
 	for i := range news {
 		news[i].AudioURL = cleanAudioURLInGet(news[i].AudioURL)
 	}
 
 	return c.JSON(http.StatusOK, GetNewsResponse{
-		News:  news,
-		Total: total,
-		Page:  page,
-		Limit: limit,
+		News:       news,
+		Total:      total,
+		Limit:      query.limit,
+		NextCursor: nextCursor,
 	})
 }
 
@@ -199,6 +169,53 @@ func GetSingleNews(c echo.Context) error {
 		})
 	}
 
+	// Remember when the user first opened the article; read state is only changed explicitly
+	if news.OpenedAt == nil {
+		now := time.Now()
+		_, err = newsCollection.UpdateOne(context.Background(),
+			bson.M{"_id": news.ID, "opened_at": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{"opened_at": now}},
+		)
+		if err == nil {
+			news.OpenedAt = &now
+		}
+	}
+
+	// Articles generated before highlights existed get them computed and saved on first open.
+	// An article without matches stores an empty list, so this runs only once.
+	if news.HighlightSpans == nil && news.Content != "" {
+		news.HighlightSpans, news.WordInNews = computeHighlights(news.Content, news.WordInNews, news.Keywords)
+		_, err = newsCollection.UpdateOne(context.Background(),
+			bson.M{"_id": news.ID},
+			bson.M{"$set": bson.M{
+				"highlight_spans": news.HighlightSpans,
+				"word_in_news":    news.WordInNews,
+			}},
+		)
+		if err != nil {
+			log.Printf("Warning: Failed to save highlights for news %s: %v", news.ID, err)
+		}
+	}
+
+	// Articles generated before readability was measured get it computed and saved on first open
+	if news.Readability == nil && news.Content != "" {
+		knownWords, _ := getUserKnownWords(userID) // Ignore error, measure against common words only
+		readability := nlp.MeasureReadability(news.Content, knownWords)
+		news.Readability = &readability
+		news.ReportedLevel, news.Level = news.Level, readability.Level // The stored level was Gemini's own report
+		_, err = newsCollection.UpdateOne(context.Background(),
+			bson.M{"_id": news.ID},
+			bson.M{"$set": bson.M{
+				"readability":    news.Readability,
+				"level":          news.Level,
+				"reported_level": news.ReportedLevel,
+			}},
+		)
+		if err != nil {
+			log.Printf("Warning: Failed to save readability for news %s: %v", news.ID, err)
+		}
+	}
+
 	// Clean audio URL to ensure it only contains the path
 	news.AudioURL = cleanAudioURLInGet(news.AudioURL)
 
@@ -0,0 +1,220 @@
+package news
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"google-devjam-backend/model"
+	"google-devjam-backend/utils/gemini"
+	"google-devjam-backend/utils/middleware"
+	"google-devjam-backend/utils/mongodb"
+	"google-devjam-backend/utils/nlp"
+)
+
+const (
+	// glossBatchSize is how many sentences are translated per Gemini call; batches run concurrently
+	glossBatchSize = 20
+	// glossTimeout bounds glossing a whole article
+	glossTimeout = 90 * time.Second
+	// glossAttempts is how many times a batch is sent while Gemini leaves some of its sentences out
+	glossAttempts = 3
+)
+
+type GlossResponse struct {
+	NewsID    string                  `json:"news_id"`
+	Sentences []model.GlossedSentence `json:"sentences"`
+}
+
+// GetNewsGloss returns a translation of every sentence of an article with glosses for words above the user's level.
+// The gloss is generated on first request and cached on the article.
+func GetNewsGloss(c echo.Context) error {
+	// Get user info from context
+	userID, _ := middleware.GetUserFromContext(c)
+	if userID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "User not authenticated",
+		})
+	}
+
+	newsID := c.Param("id")
+	if newsID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "News ID is required",
+		})
+	}
+
+	newsCollection := mongodb.GetCollection("news")
+	if newsCollection == nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Database connection error",
+		})
+	}
+
+	var news model.News
+	err := newsCollection.FindOne(context.Background(), bson.M{
+		"_id":     newsID,
+		"user_id": userID,
+	}).Decode(&news)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "News article not found",
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Database error",
+		})
+	}
+
+	if len(news.Gloss) > 0 {
+		return c.JSON(http.StatusOK, GlossResponse{
+			NewsID:    news.ID,
+			Sentences: news.Gloss,
+		})
+	}
+
+	knownWords, err := getUserKnownWords(userID)
+	if err != nil {
+		knownWords = nil // Continue with common words only
+	}
+
+	level := 0 // Unknown level glosses only words outside the whole common word list
+	if preferences, err := getUserPreferences(userID); err == nil && preferences != nil {
+		level = preferences.Level
+	}
+
+	sentences, complete, err := glossContent(news.Content, knownWords, level)
+	if err != nil {
+		log.Printf("Warning: Failed to gloss news %s: %v", news.ID, err)
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{
+			"error": "Failed to translate article",
+		})
+	}
+
+	// An incomplete gloss is still shown, but not cached, so the next request can fill the gaps
+	if !complete {
+		log.Printf("Warning: Gloss of news %s is missing sentences, not caching it", news.ID)
+	} else if _, err := newsCollection.UpdateOne(context.Background(),
+		bson.M{"_id": news.ID},
+		bson.M{"$set": bson.M{"gloss": sentences}},
+	); err != nil {
+		log.Printf("Warning: Failed to cache gloss for news %s: %v", news.ID, err)
+	}
+
+	return c.JSON(http.StatusOK, GlossResponse{
+		NewsID:    news.ID,
+		Sentences: sentences,
+	})
+}
+
+// glossContent splits content into sentences and translates and glosses them through Gemini in concurrent batches.
+// Words above the learner's level are sent to be glossed. Sentences Gemini leaves out are sent again, up to
+// glossAttempts times per batch; complete reports whether every sentence ended up translated.
+func glossContent(content string, knownWords map[string]bool, level int) (sentences []model.GlossedSentence, complete bool, err error) {
+	spans := nlp.Sentences(content)
+
+	inputs := make([]gemini.GlossInput, len(spans))
+	for i, span := range spans {
+		inputs[i] = gemini.GlossInput{
+			Index:     i,
+			Text:      span.Text,
+			HardWords: nlp.HardWords(span.Text, knownWords, level),
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), glossTimeout)
+	defer cancel()
+
+	glosses := make(map[int]gemini.SentenceGloss, len(spans))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var firstErr error
+
+	for start := 0; start < len(inputs); start += glossBatchSize {
+		batch := inputs[start:min(start+glossBatchSize, len(inputs))]
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			pending := batch
+			var lastErr error
+			for attempt := 1; attempt <= glossAttempts && len(pending) > 0; attempt++ {
+				results, err := gemini.GlossSentences(ctx, pending)
+				lastErr = err
+				if err != nil {
+					continue
+				}
+
+				mu.Lock()
+				// Only accept sentences that were asked for, so a wrong index cannot overwrite another batch
+				asked := make(map[int]bool, len(pending))
+				for _, input := range pending {
+					asked[input.Index] = true
+				}
+				for _, result := range results {
+					if asked[result.Index] && result.Translation != "" {
+						glosses[result.Index] = result
+					}
+				}
+				var missing []gemini.GlossInput
+				for _, input := range pending {
+					if _, ok := glosses[input.Index]; !ok {
+						missing = append(missing, input)
+					}
+				}
+				mu.Unlock()
+				pending = missing
+			}
+
+			if lastErr != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = lastErr
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, false, firstErr
+	}
+
+	sentences = make([]model.GlossedSentence, len(spans))
+	for i, span := range spans {
+		sentence := model.GlossedSentence{
+			Start:       span.Start,
+			End:         span.End,
+			Text:        span.Text,
+			Translation: glosses[i].Translation,
+			Glosses:     []model.GlossedWord{},
+		}
+
+		// Locate each glossed word in its sentence; glosses for words Gemini invented are dropped
+		for _, gloss := range glosses[i].Glosses {
+			matches := nlp.FindWordMatches(span.Text, []string{gloss.Word})
+			if len(matches) == 0 || gloss.Gloss == "" {
+				continue
+			}
+			sentence.Glosses = append(sentence.Glosses, model.GlossedWord{
+				Word:  gloss.Word,
+				Gloss: gloss.Gloss,
+				Start: span.Start + matches[0].Start,
+				End:   span.Start + matches[0].End,
+			})
+		}
+
+		sentences[i] = sentence
+	}
+
+	return sentences, len(glosses) == len(spans), nil
+}
@@ -0,0 +1,58 @@
+package news
+
+import (
+	"sort"
+
+	"google-devjam-backend/model"
+	"google-devjam-backend/utils/nlp"
+)
+
+// computeHighlights finds where the vocabulary words and keywords occur in an article's content.
+// It returns the spans ordered by position and the vocabulary words that actually appear, in their original order.
+// Keyword spans that overlap a vocabulary span are dropped so the client never has to resolve nested highlights.
+func computeHighlights(content string, vocabWords, keywords []string) ([]model.HighlightSpan, []string) {
+	spans := []model.HighlightSpan{}
+	usedWords := []string{}
+
+	found := make(map[string]bool)
+	vocabMatches := nlp.FindWordMatches(content, vocabWords)
+	for _, match := range vocabMatches {
+		spans = append(spans, toHighlightSpan(match, model.HighlightVocabulary))
+		found[match.Word] = true
+	}
+	for _, word := range vocabWords {
+		if found[word] {
+			usedWords = append(usedWords, word)
+			delete(found, word) // Keep duplicates in the input from repeating in the output
+		}
+	}
+
+	for _, match := range nlp.FindWordMatches(content, keywords) {
+		overlaps := false
+		for _, vocab := range vocabMatches {
+			if match.Start < vocab.End && vocab.Start < match.End {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			spans = append(spans, toHighlightSpan(match, model.HighlightKeyword))
+		}
+	}
+
+	sort.SliceStable(spans, func(i, j int) bool {
+		return spans[i].Start < spans[j].Start
+	})
+
+	return spans, usedWords
+}
+
+func toHighlightSpan(match nlp.WordMatch, kind string) model.HighlightSpan {
+	return model.HighlightSpan{
+		Word:  match.Word,
+		Kind:  kind,
+		Start: match.Start,
+		End:   match.End,
+		Text:  match.Text,
+	}
+}
@@ -0,0 +1,339 @@
+package news
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"google-devjam-backend/model"
+	"google-devjam-backend/utils/encrypt"
+	"google-devjam-backend/utils/feeds"
+	"google-devjam-backend/utils/gemini"
+	"google-devjam-backend/utils/mongodb"
+	"google-devjam-backend/utils/nlp"
+)
+
+const (
+	sourceArticlesCollection = "source_articles"
+
+	defaultFeedPollInterval = time.Hour
+	// sourceArticleMaxAge is how long an ingested article stays current enough to rewrite
+	sourceArticleMaxAge = 72 * time.Hour
+	// maxItemsPerFeed bounds how many new items one poll takes from a feed
+	maxItemsPerFeed = 20
+	// minSourceArticleRunes is the shortest article text worth rewriting; shorter text is usually a teaser
+	minSourceArticleRunes = 800
+	// feedFetchTimeout bounds fetching one feed or article page
+	feedFetchTimeout = 60 * time.Second
+	// rewriteTimeout bounds the Gemini call that rewrites one article
+	rewriteTimeout = 2 * time.Minute
+)
+
+var errSourceArticleExists = errors.New("source article already stored")
+
+// sourceArticleStore keeps ingested source articles, keyed by URL
+type sourceArticleStore interface {
+	// HasURL reports whether an article with the URL is stored
+	HasURL(ctx context.Context, url string) (bool, error)
+	// Insert stores an article, returning errSourceArticleExists if its URL is already stored
+	Insert(ctx context.Context, article model.SourceArticle) error
+}
+
+// mongoSourceArticleStore stores source articles in the source_articles collection, whose unique url index
+// catches articles listed by more than one feed
+type mongoSourceArticleStore struct {
+	collection *mongo.Collection
+}
+
+func (s mongoSourceArticleStore) HasURL(ctx context.Context, url string) (bool, error) {
+	count, err := s.collection.CountDocuments(ctx, bson.M{"url": url}, options.Count().SetLimit(1))
+	return count > 0, err
+}
+
+func (s mongoSourceArticleStore) Insert(ctx context.Context, article model.SourceArticle) error {
+	_, err := s.collection.InsertOne(ctx, article)
+	if mongo.IsDuplicateKeyError(err) {
+		return errSourceArticleExists
+	}
+	return err
+}
+
+// configuredFeeds returns the feed URLs in NEWS_FEEDS, separated by commas or whitespace
+func configuredFeeds() []string {
+	return strings.FieldsFunc(os.Getenv("NEWS_FEEDS"), func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\n' || r == '\t'
+	})
+}
+
+// StartFeedIngestion polls the RSS and Atom feeds listed in NEWS_FEEDS every NEWS_FEED_POLL_INTERVAL (default 1h)
+// and stores their new articles, which news jobs then rewrite at each user's level.
+// Ingestion is off when NEWS_FEEDS is empty or NEWS_FEED_POLL_INTERVAL is "off".
+// Feeds and article pages on loopback or private addresses are refused unless NEWS_FEEDS_ALLOW_PRIVATE is "on",
+// which is meant for a local feed server during development.
+func StartFeedIngestion() {
+	feedURLs := configuredFeeds()
+	if len(feedURLs) == 0 || os.Getenv("NEWS_FEED_POLL_INTERVAL") == "off" {
+		log.Println("News feed ingestion is disabled")
+		return
+	}
+
+	interval := durationFromEnv("NEWS_FEED_POLL_INTERVAL", defaultFeedPollInterval)
+	client := feeds.NewClientWithConfig(feeds.Config{
+		AllowPrivateAddresses: os.Getenv("NEWS_FEEDS_ALLOW_PRIVATE") == "on",
+	})
+	log.Printf("News feed ingestion polling %d feeds every %s", len(feedURLs), interval)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			ingestFeeds(client, feedURLs)
+			<-ticker.C
+		}
+	}()
+}
+
+// ingestFeeds stores the new, recent articles of every feed. A failing feed is logged and skipped.
+func ingestFeeds(client *feeds.Client, feedURLs []string) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Error: News feed ingestion panicked: %v", r)
+		}
+	}()
+
+	sourcesCollection := mongodb.GetCollection(sourceArticlesCollection)
+	if sourcesCollection == nil {
+		log.Printf("Warning: Skipping news feed ingestion: %v", mongo.ErrClientDisconnected)
+		return
+	}
+	store := mongoSourceArticleStore{collection: sourcesCollection}
+
+	added := 0
+	for _, feedURL := range feedURLs {
+		count, err := ingestFeed(client, store, feedURL)
+		if err != nil {
+			log.Printf("Warning: Failed to ingest feed %s: %v", feedURL, err)
+		}
+		added += count
+	}
+
+	if added > 0 {
+		log.Printf("Ingested %d new source articles", added)
+	}
+}
+
+// ingestFeed stores the feed's new, recent articles and returns how many were added.
+// Article text comes from the feed when it carries the full content, and from the article page otherwise.
+func ingestFeed(client *feeds.Client, store sourceArticleStore, feedURL string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), feedFetchTimeout)
+	items, err := client.Fetch(ctx, feedURL)
+	cancel()
+	if err != nil {
+		return 0, err
+	}
+	if len(items) > maxItemsPerFeed {
+		items = items[:maxItemsPerFeed]
+	}
+
+	cutoff := time.Now().Add(-sourceArticleMaxAge)
+	added := 0
+	for _, item := range items {
+		if !item.PublishedAt.IsZero() && item.PublishedAt.Before(cutoff) {
+			continue
+		}
+
+		known, err := store.HasURL(context.Background(), item.Link)
+		if err != nil {
+			return added, err
+		}
+		if known {
+			continue
+		}
+
+		text := item.Content
+		if len([]rune(text)) < minSourceArticleRunes {
+			ctx, cancel := context.WithTimeout(context.Background(), feedFetchTimeout)
+			text, err = client.ExtractArticle(ctx, item.Link)
+			cancel()
+			if err != nil {
+				log.Printf("Warning: Failed to extract article %s: %v", item.Link, err)
+				continue
+			}
+		}
+		if len([]rune(text)) < minSourceArticleRunes {
+			continue // Paywalled or a teaser; not enough to retell
+		}
+
+		sourceID, err := encrypt.GenerateSnowflakeID()
+		if err != nil {
+			return added, err
+		}
+
+		now := time.Now()
+		publishedAt := item.PublishedAt
+		if publishedAt.IsZero() {
+			publishedAt = now
+		}
+		publisher := item.FeedTitle
+		if publisher == "" {
+			publisher = feedURL
+		}
+
+		err = store.Insert(context.Background(), model.SourceArticle{
+			ID:          sourceID,
+			FeedURL:     feedURL,
+			FeedTitle:   publisher,
+			URL:         item.Link,
+			GUID:        item.GUID,
+			Title:       item.Title,
+			Text:        text,
+			PublishedAt: publishedAt,
+			FetchedAt:   now,
+		})
+		if err != nil {
+			if errors.Is(err, errSourceArticleExists) {
+				continue // Also listed by another feed
+			}
+			return added, err
+		}
+		added++
+	}
+
+	return added, nil
+}
+
+// pickSourceArticles returns up to count recent source articles the user has no rewrite of and that are not known
+// to fail the safety checks, best match first.
+// Users with interests only get articles about them; others get the newest.
+func pickSourceArticles(userID string, interests []string, count int) []model.SourceArticle {
+	if count <= 0 || len(configuredFeeds()) == 0 {
+		return nil
+	}
+
+	sourcesCollection := mongodb.GetCollection(sourceArticlesCollection)
+	newsCollection := mongodb.GetCollection("news")
+	quarantineCollection := mongodb.GetCollection(quarantinedNewsCollection)
+	if sourcesCollection == nil || newsCollection == nil || quarantineCollection == nil {
+		return nil
+	}
+
+	rewritten, err := newsCollection.Distinct(context.Background(), "original.source_article_id", bson.M{
+		"user_id":                    userID,
+		"original.source_article_id": bson.M{"$exists": true},
+	})
+	if err != nil {
+		log.Printf("Warning: Failed to get rewritten source articles of user %s: %v", userID, err)
+		return nil
+	}
+
+	// Retellings of these sources failed the safety checks, which would likely happen again. A source that failed
+	// the stricter kid-safe rules is only skipped for that user; one that failed the general rules, for everyone.
+	unsafe, err := quarantineCollection.Distinct(context.Background(), "news.original.source_article_id", bson.M{
+		"news.original.source_article_id": bson.M{"$exists": true},
+		"$or": []bson.M{
+			{"user_id": userID},
+			{"kid_safe": false},
+		},
+	})
+	if err != nil {
+		log.Printf("Warning: Failed to get quarantined source articles for user %s: %v", userID, err)
+		return nil
+	}
+
+	cursor, err := sourcesCollection.Find(
+		context.Background(),
+		bson.M{
+			"_id":          bson.M{"$nin": append(rewritten, unsafe...)},
+			"published_at": bson.M{"$gte": time.Now().Add(-sourceArticleMaxAge)},
+		},
+		options.Find().
+			SetSort(bson.D{{Key: "published_at", Value: -1}}).
+			SetLimit(poolCandidateLimit),
+	)
+	if err != nil {
+		log.Printf("Warning: Failed to search source articles for user %s: %v", userID, err)
+		return nil
+	}
+	defer cursor.Close(context.Background())
+
+	var candidates []model.SourceArticle
+	if err := cursor.All(context.Background(), &candidates); err != nil {
+		log.Printf("Warning: Failed to read source articles for user %s: %v", userID, err)
+		return nil
+	}
+
+	if len(interests) == 0 {
+		if len(candidates) > count {
+			candidates = candidates[:count]
+		}
+		return candidates
+	}
+
+	type scoredSource struct {
+		source model.SourceArticle
+		score  int
+	}
+	var matching []scoredSource
+	for _, source := range candidates {
+		mentioned := make(map[string]bool)
+		for _, match := range nlp.FindWordMatches(source.Title+". "+excerpt(source.Text, 1500), interests) {
+			mentioned[match.Word] = true
+		}
+		if len(mentioned) > 0 {
+			matching = append(matching, scoredSource{source: source, score: len(mentioned)})
+		}
+	}
+	// Candidates are newest first, so a stable sort keeps newer articles ahead on equal scores
+	sort.SliceStable(matching, func(i, j int) bool {
+		return matching[i].score > matching[j].score
+	})
+
+	var picked []model.SourceArticle
+	for _, candidate := range matching {
+		if len(picked) == count {
+			break
+		}
+		picked = append(picked, candidate.source)
+	}
+	return picked
+}
+
+// rewriteSourceArticle asks Gemini to retell a source article at the user's level and builds the news document
+// without audio, crediting the original
+func rewriteSourceArticle(userID string, newsReq gemini.NewsGenerationRequest, source model.SourceArticle, knownWords map[string]bool) (*model.News, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), rewriteTimeout)
+	newsResult, err := gemini.RewriteNewsArticle(ctx, newsReq, gemini.OriginalArticle{
+		Title:     source.Title,
+		Text:      source.Text,
+		Publisher: source.FeedTitle,
+	})
+	cancel()
+	if err != nil {
+		return nil, err
+	}
+
+	newsResult.Source = []string{source.FeedTitle}
+	newsResult.Sources = []model.NewsSource{{URL: source.URL, Title: source.Title}}
+
+	news, err := buildNewsArticle(userID, newsReq, newsResult, knownWords)
+	if err != nil {
+		return nil, err
+	}
+	news.Original = &model.NewsOriginal{
+		SourceArticleID: source.ID,
+		URL:             source.URL,
+		Title:           source.Title,
+		Publisher:       source.FeedTitle,
+		PublishedAt:     source.PublishedAt,
+	}
+	return news, nil
+}
@@ -0,0 +1,111 @@
+package news
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"google-devjam-backend/model"
+	"google-devjam-backend/utils/feeds"
+)
+
+// memorySourceArticleStore keeps source articles in memory, keyed by URL like the unique index does
+type memorySourceArticleStore struct {
+	articles map[string]model.SourceArticle
+}
+
+func (s *memorySourceArticleStore) HasURL(ctx context.Context, url string) (bool, error) {
+	_, ok := s.articles[url]
+	return ok, nil
+}
+
+func (s *memorySourceArticleStore) Insert(ctx context.Context, article model.SourceArticle) error {
+	if _, ok := s.articles[article.URL]; ok {
+		return errSourceArticleExists
+	}
+	s.articles[article.URL] = article
+	return nil
+}
+
+// longText returns paragraphs totalling well over minSourceArticleRunes
+func longText(topic string) string {
+	paragraph := "This paragraph about " + topic + " is long enough to be kept when the article page is extracted."
+	return strings.Repeat("<p>"+paragraph+"</p>", 12)
+}
+
+func TestIngestFeedRules(t *testing.T) {
+	recent := time.Now().Add(-time.Hour).Format(time.RFC1123Z)
+	old := time.Now().Add(-sourceArticleMaxAge - 24*time.Hour).Format(time.RFC1123Z)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/long.html", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "<html><body><article>%s</article></body></html>", longText("the teaser"))
+	})
+	mux.HandleFunc("/short.html", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "<html><body><article><p>Subscribe to read the rest of this story on our website.</p></article></body></html>")
+	})
+	mux.HandleFunc("/feed.xml", func(w http.ResponseWriter, r *http.Request) {
+		base := "http://" + r.Host
+		item := func(title, link, date, content string) string {
+			return fmt.Sprintf(`<item><title>%s</title><link>%s</link><pubDate>%s</pubDate><content:encoded><![CDATA[%s]]></content:encoded></item>`,
+				title, link, date, content)
+		}
+		fmt.Fprintf(w, `<rss version="2.0" xmlns:content="http://purl.org/rss/1.0/modules/content/"><channel><title>Local News</title>%s</channel></rss>`,
+			item("Full article", base+"/full", recent, longText("the full article"))+
+				item("Teaser with a full page", base+"/long.html", recent, "<p>Read more.</p>")+
+				item("Teaser with a short page", base+"/short.html", recent, "<p>Read more.</p>")+
+				item("Old article", base+"/old", old, longText("an old story"))+
+				item("Already stored", base+"/stored", recent, longText("a stored story"))+
+				item("Full article again", base+"/full", recent, longText("the full article")),
+		)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	store := &memorySourceArticleStore{articles: map[string]model.SourceArticle{
+		server.URL + "/stored": {URL: server.URL + "/stored"},
+	}}
+	client := feeds.NewClientWithConfig(feeds.Config{AllowPrivateAddresses: true})
+
+	added, err := ingestFeed(client, store, server.URL+"/feed.xml")
+	if err != nil {
+		t.Fatalf("ingestFeed: %v", err)
+	}
+
+	if added != 2 {
+		t.Errorf("added %d articles, want 2", added)
+	}
+
+	full, ok := store.articles[server.URL+"/full"]
+	if !ok {
+		t.Fatal("article with full content in the feed was not stored")
+	}
+	if full.FeedTitle != "Local News" || full.Title != "Full article" || full.FeedURL != server.URL+"/feed.xml" {
+		t.Errorf("unexpected stored article: %+v", full)
+	}
+	if !strings.Contains(full.Text, "about the full article") {
+		t.Errorf("stored text %q does not come from the feed content", excerpt(full.Text, 80))
+	}
+
+	teaser, ok := store.articles[server.URL+"/long.html"]
+	if !ok {
+		t.Fatal("teaser whose page has the full article was not stored")
+	}
+	if !strings.Contains(teaser.Text, "about the teaser") || len([]rune(teaser.Text)) < minSourceArticleRunes {
+		t.Errorf("teaser text %q was not extracted from its page", excerpt(teaser.Text, 80))
+	}
+
+	if _, ok := store.articles[server.URL+"/short.html"]; ok {
+		t.Errorf("stored a teaser whose page is under %d runes", minSourceArticleRunes)
+	}
+	if _, ok := store.articles[server.URL+"/old"]; ok {
+		t.Errorf("stored an article older than %s", sourceArticleMaxAge)
+	}
+	if store.articles[server.URL+"/stored"].Title != "" {
+		t.Error("overwrote an article that was already stored")
+	}
+}
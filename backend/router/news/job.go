@@ -0,0 +1,610 @@
+package news
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"google-devjam-backend/model"
+	"google-devjam-backend/utils/encrypt"
+	"google-devjam-backend/utils/gemini"
+	"google-devjam-backend/utils/middleware"
+	"google-devjam-backend/utils/mongodb"
+	"google-devjam-backend/utils/nlp"
+	"google-devjam-backend/utils/services"
+)
+
+const newsJobsCollection = "news_jobs"
+
+const (
+	// newsTextWorkerCount bounds concurrent Gemini calls per job; a batch is 4 articles, so they all run at once
+	newsTextWorkerCount = 4
+	// newsAudioWorkerCount bounds concurrent TTS syntheses per job, which are far heavier than text generation
+	newsAudioWorkerCount = 2
+	// topicPlanTimeout bounds the single call that picks the batch's topics
+	topicPlanTimeout = 60 * time.Second
+	// newsJobHeartbeatInterval is how often a running job bumps updated_at to show its process is alive
+	newsJobHeartbeatInterval = 30 * time.Second
+	// newsJobStaleAfter is how long a pending or running job may go without an update before it is considered
+	// abandoned by a process that stopped
+	newsJobStaleAfter = 4 * newsJobHeartbeatInterval
+)
+
+// userLocks holds one mutex per user, so requests and the scheduler cannot start two jobs for the same user
+var userLocks sync.Map
+
+// lockUser acquires the user's generation lock and returns the function that releases it
+func lockUser(userID string) func() {
+	value, _ := userLocks.LoadOrStore(userID, &sync.Mutex{})
+	mu := value.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
+}
+
+type NewsJobResponse struct {
+	Job model.NewsGenerationJob `json:"job"`
+}
+
+// startNewsJob creates a generation job for the given number of articles and runs it in the background.
+// If the user already has a job in progress, that job is returned instead and started is false.
+func startNewsJob(userID string, count int, force bool) (job *model.NewsGenerationJob, started bool, err error) {
+	unlock := lockUser(userID)
+	job, started, err = createNewsJob(userID, count, force, false)
+	unlock()
+	if err != nil {
+		return nil, false, err
+	}
+
+	if started {
+		go runNewsJob(*job)
+	}
+
+	return job, started, nil
+}
+
+// createNewsJob stores a pending job unless the user already has one in progress, in which case that job is
+// returned and created is false. The caller must hold the user's lock and is responsible for running the job.
+func createNewsJob(userID string, count int, force, scheduled bool) (job *model.NewsGenerationJob, created bool, err error) {
+	jobsCollection := mongodb.GetCollection(newsJobsCollection)
+	if jobsCollection == nil {
+		return nil, false, mongo.ErrClientDisconnected
+	}
+
+	active, err := getActiveNewsJob(userID)
+	if err != nil {
+		return nil, false, err
+	}
+	if active != nil {
+		return active, false, nil
+	}
+
+	jobID, err := encrypt.GenerateSnowflakeID()
+	if err != nil {
+		return nil, false, err
+	}
+
+	articles := make([]model.NewsJobArticle, count)
+	for i := range articles {
+		articles[i].Status = model.NewsArticlePending
+	}
+
+	now := time.Now()
+	job = &model.NewsGenerationJob{
+		ID:        jobID,
+		UserID:    userID,
+		Status:    model.NewsJobPending,
+		Force:     force,
+		Scheduled: scheduled,
+		Articles:  articles,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if _, err := jobsCollection.InsertOne(context.Background(), job); err != nil {
+		return nil, false, err
+	}
+
+	return job, true, nil
+}
+
+// getActiveNewsJob returns the user's pending or running job, or nil if there is none.
+// A job whose process stopped heartbeating is failed here, so it does not block the user forever.
+func getActiveNewsJob(userID string) (*model.NewsGenerationJob, error) {
+	jobsCollection := mongodb.GetCollection(newsJobsCollection)
+	if jobsCollection == nil {
+		return nil, mongo.ErrClientDisconnected
+	}
+
+	var job model.NewsGenerationJob
+	err := jobsCollection.FindOne(
+		context.Background(),
+		bson.M{
+			"user_id": userID,
+			"status":  bson.M{"$in": []string{model.NewsJobPending, model.NewsJobRunning}},
+		},
+		options.FindOne().SetSort(bson.D{{Key: "created_at", Value: -1}}),
+	).Decode(&job)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if time.Since(job.UpdatedAt) > newsJobStaleAfter {
+		if err := failStaleNewsJobs(bson.M{"user_id": userID}); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	return &job, nil
+}
+
+// runNewsJob generates the job's articles concurrently, recording progress and publishing events after every step.
+// Topics are planned first so concurrent articles do not overlap. Each article is stored as soon as its text is
+// ready; audio is synthesized afterwards through a smaller pool, since TTS is the slowest and most limited step.
+// A failed article is recorded and skipped so the rest of the batch still gets generated.
+func runNewsJob(job model.NewsGenerationJob) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Error: News job %s panicked: %v", job.ID, r)
+			finishNewsJob(job.ID, model.NewsJobFailed, fmt.Sprintf("internal error: %v", r))
+		}
+	}()
+
+	updateNewsJob(job.ID, bson.M{"status": model.NewsJobRunning})
+	defer startNewsJobHeartbeat(job.ID)()
+
+	// storedIDs are the articles this job added, which a force job keeps when it archives the rest
+	var storedMu sync.Mutex
+	var storedIDs []string
+	stored := func(newsID string) {
+		storedMu.Lock()
+		storedIDs = append(storedIDs, newsID)
+		storedMu.Unlock()
+	}
+
+	newsCollection := mongodb.GetCollection("news")
+	if newsCollection == nil {
+		finishNewsJob(job.ID, model.NewsJobFailed, "Database connection error")
+		return
+	}
+
+	// Force generation replaces the user's current news, so their titles need not be avoided
+	var existingTitles []string
+	if !job.Force {
+		allNews, err := getAllUserNews(job.UserID)
+		if err != nil {
+			finishNewsJob(job.ID, model.NewsJobFailed, "Failed to get user news: "+err.Error())
+			return
+		}
+		for _, news := range allNews {
+			existingTitles = append(existingTitles, news.Title)
+		}
+	}
+
+	userPreferences, err := getUserPreferences(job.UserID)
+	if err != nil {
+		// Continue without preferences if not found
+		userPreferences = nil
+	}
+
+	learnWords, reviewWords, err := getUserVocabularyForNews(job.UserID)
+	if err != nil {
+		finishNewsJob(job.ID, model.NewsJobFailed, "Failed to get user vocabulary: "+err.Error())
+		return
+	}
+
+	knownWords, err := getUserKnownWords(job.UserID)
+	if err != nil {
+		log.Printf("Warning: News job %s failed to get known words, measuring against common words only: %v", job.ID, err)
+		knownWords = nil
+	}
+
+	// Fill what the shared pool can with articles already written for similar users; only the rest is generated
+	pooled := takePooledNews(job.UserID, newNewsProfile(userPreferences, learnWords, reviewWords, knownWords), len(job.Articles))
+	for i, news := range pooled {
+		if _, err := newsCollection.InsertOne(context.Background(), news); err != nil {
+			failNewsJobArticle(job.ID, i, model.NewsJobArticle{
+				Title: news.Title,
+				Error: "Failed to store news: " + err.Error(),
+			})
+			continue
+		}
+		stored(news.ID)
+		existingTitles = append(existingTitles, news.Title)
+
+		updateNewsJobArticle(job.ID, i, model.NewsJobArticle{
+			Status: model.NewsArticleCompleted,
+			NewsID: news.ID,
+			Title:  news.Title,
+		})
+		news.AudioURL = cleanAudioURL(news.AudioURL)
+		jobEvents.publish(NewsJobEvent{Type: NewsEventTextReady, JobID: job.ID, Index: i, News: news})
+		jobEvents.publish(NewsJobEvent{Type: NewsEventAudioReady, JobID: job.ID, Index: i, News: news})
+	}
+
+	var interests []string
+	kidSafe := false
+	if userPreferences != nil {
+		interests = userPreferences.Interests
+		kidSafe = userPreferences.KidSafe
+	}
+
+	// Prefer retelling real articles from the configured feeds over writing stories from scratch
+	sources := pickSourceArticles(job.UserID, interests, len(job.Articles)-len(pooled))
+
+	baseReq := gemini.NewsGenerationRequest{
+		UserPreferences: userPreferences,
+		LearnWords:      learnWords,
+		ReviewWords:     reviewWords,
+		ExistingTitles:  existingTitles,
+	}
+
+	// Decide every topic up front so the articles can be written concurrently without overlapping
+	var topics []string
+	if toGenerate := len(job.Articles) - len(pooled) - len(sources); toGenerate > 0 {
+		planCtx, cancel := context.WithTimeout(context.Background(), topicPlanTimeout)
+		topics, err = gemini.PlanNewsTopics(planCtx, baseReq, toGenerate)
+		cancel()
+		if err != nil {
+			// Still generate the batch; each article is only told about the titles that existed before it
+			log.Printf("Warning: News job %s failed to plan topics, generating without them: %v", job.ID, err)
+		}
+	}
+
+	// One audio service for the whole batch instead of probing TTS for every article
+	audioService, err := services.SharedAudioService()
+	if err != nil {
+		log.Printf("Warning: Failed to initialize audio service: %v", err)
+		audioService = nil // Continue without audio generation
+	}
+
+	textSem := make(chan struct{}, newsTextWorkerCount)
+	audioSem := make(chan struct{}, newsAudioWorkerCount)
+
+	var wg sync.WaitGroup
+	for i := len(pooled); i < len(job.Articles); i++ {
+		newsReq := baseReq
+		var source *model.SourceArticle
+		if k := i - len(pooled); k < len(sources) {
+			source = &sources[k]
+		} else if t := k - len(sources); t < len(topics) {
+			newsReq.Topic = topics[t]
+		}
+
+		wg.Add(1)
+		go func(i int, newsReq gemini.NewsGenerationRequest, source *model.SourceArticle) {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					log.Printf("Error: News job %s panicked on article %d: %v", job.ID, i, r)
+					failNewsJobArticle(job.ID, i, model.NewsJobArticle{Error: fmt.Sprintf("internal error: %v", r)})
+				}
+			}()
+
+			textSem <- struct{}{}
+			updateNewsJobArticle(job.ID, i, model.NewsJobArticle{Status: model.NewsArticleGenerating})
+			news, err := writeSafeArticle(job.UserID, newsReq, source, knownWords, kidSafe)
+			<-textSem
+			if errors.Is(err, errArticleUnsafe) {
+				log.Printf("Warning: News job %s gave up on article %d after %d unsafe attempts", job.ID, i, maxArticleAttempts)
+				failNewsJobArticle(job.ID, i, model.NewsJobArticle{Error: "Article failed content safety checks"})
+				return
+			}
+			if err != nil {
+				log.Printf("Warning: News job %s failed to generate article %d: %v", job.ID, i, err)
+				failNewsJobArticle(job.ID, i, model.NewsJobArticle{
+					Error: "Failed to generate news: " + err.Error(),
+				})
+				return
+			}
+
+			// Store the text right away so the article is readable while its audio is produced
+			if _, err := newsCollection.InsertOne(context.Background(), news); err != nil {
+				failNewsJobArticle(job.ID, i, model.NewsJobArticle{
+					Title: news.Title,
+					Error: "Failed to store news: " + err.Error(),
+				})
+				return
+			}
+			stored(news.ID)
+
+			updateNewsJobArticle(job.ID, i, model.NewsJobArticle{
+				Status: model.NewsArticleAudio,
+				NewsID: news.ID,
+				Title:  news.Title,
+			})
+			jobEvents.publish(NewsJobEvent{Type: NewsEventTextReady, JobID: job.ID, Index: i, News: news})
+
+			textSem <- struct{}{}
+			attachNewsQuestions(news)
+			<-textSem
+
+			if audioService != nil {
+				audioSem <- struct{}{}
+				jobEvents.publish(NewsJobEvent{Type: NewsEventAudioUploading, JobID: job.ID, Index: i})
+				attached := attachNewsAudio(audioService, news)
+				<-audioSem
+
+				if attached {
+					_, err := newsCollection.UpdateOne(context.Background(),
+						bson.M{"_id": news.ID},
+						bson.M{"$set": bson.M{
+							"audio_url":     news.AudioURL,
+							"audio_key":     news.AudioKey,
+							"captions_url":  news.CaptionsURL,
+							"captions_key":  news.CaptionsKey,
+							"audio_timings": news.AudioTimings,
+							"updated_at":    time.Now(),
+						}},
+					)
+					if err != nil {
+						log.Printf("Warning: Failed to save audio for news %s: %v", news.ID, err)
+						news.AudioURL, news.AudioKey = "", ""
+						news.CaptionsURL, news.CaptionsKey, news.AudioTimings = "", "", nil
+					}
+				}
+			}
+
+			addToPool(news, interests, kidSafe)
+
+			updateNewsJobArticle(job.ID, i, model.NewsJobArticle{
+				Status: model.NewsArticleCompleted,
+				NewsID: news.ID,
+				Title:  news.Title,
+			})
+			news.AudioURL = cleanAudioURL(news.AudioURL)
+			jobEvents.publish(NewsJobEvent{Type: NewsEventAudioReady, JobID: job.ID, Index: i, News: news})
+		}(i, newsReq, source)
+	}
+	wg.Wait()
+
+	if len(storedIDs) == 0 {
+		finishNewsJob(job.ID, model.NewsJobFailed, "No articles could be generated")
+		return
+	}
+
+	// Force generation replaces the user's current news with the new batch; the old articles move to history.
+	// It happens only now, so a job that fails leaves the user's news as it was.
+	if job.Force {
+		if err := archiveUserNews(job.UserID, storedIDs); err != nil {
+			finishNewsJob(job.ID, model.NewsJobFailed, "Failed to archive existing news: "+err.Error())
+			return
+		}
+	}
+	finishNewsJob(job.ID, model.NewsJobCompleted, "")
+}
+
+// generateNewsArticle asks Gemini for one article and builds the news document without audio
+// knownWords is the user's vocabulary, used to measure how much of the article is new to them.
+func generateNewsArticle(userID string, newsReq gemini.NewsGenerationRequest, knownWords map[string]bool) (*model.News, error) {
+	newsResult, err := gemini.GeneratePersonalizedNews(newsReq)
+	if err != nil {
+		return nil, err
+	}
+	return buildNewsArticle(userID, newsReq, newsResult, knownWords)
+}
+
+// buildNewsArticle turns Gemini's article into the news document without audio: it repairs vocabulary coverage,
+// locates highlights and sources, and measures readability
+func buildNewsArticle(userID string, newsReq gemini.NewsGenerationRequest, newsResult *gemini.NewsGenerationResult, knownWords map[string]bool) (*model.News, error) {
+	newsID, err := encrypt.GenerateSnowflakeID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate news ID: %v", err)
+	}
+
+	// Combine learning and review words that were sent to Gemini
+	allVocabWords := make([]string, 0, len(newsReq.LearnWords)+len(newsReq.ReviewWords))
+	allVocabWords = append(allVocabWords, newsReq.LearnWords...)
+	allVocabWords = append(allVocabWords, newsReq.ReviewWords...)
+
+	// Make sure the article really uses the requested words, repairing it if too many are missing
+	content, coverage, missingWords := ensureVocabCoverage(newsID, newsResult.Title, newsResult.Content, allVocabWords)
+
+	// Only keep the vocabulary words that really made it into the text, and locate them for highlighting
+	highlightSpans, usedWords := computeHighlights(content, allVocabWords, newsResult.Keywords)
+
+	// Measure the difficulty instead of trusting the level Gemini reports
+	readability := nlp.MeasureReadability(content, knownWords)
+	reportedLevel, err := strconv.Atoi(strings.TrimSpace(newsResult.Level))
+	if err != nil {
+		reportedLevel = 0 // Not reported
+	}
+
+	now := time.Now()
+	return &model.News{
+		ID:             newsID,
+		UserID:         userID,
+		Title:          newsResult.Title,
+		Content:        content,
+		Level:          readability.Level,
+		ReportedLevel:  reportedLevel,
+		Readability:    &readability,
+		Keywords:       newsResult.Keywords,
+		WordInNews:     usedWords,
+		VocabCoverage:  coverage,
+		MissingWords:   missingWords,
+		HighlightSpans: highlightSpans,
+		Source:         newsResult.Source,
+		Sources:        locateSourceClaims(content, newsResult.Sources),
+		SearchQueries:  newsResult.SearchQueries,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}, nil
+}
+
+// attachNewsAudio generates and stores audio for the news content and reports whether it succeeded.
+// Audio is optional, so failures are logged and the article is kept without it.
+func attachNewsAudio(audioService *services.AudioService, news *model.News) bool {
+	audio, err := audioService.GenerateAndStoreTimedAudio(news.Content, news.ID)
+	if err != nil {
+		log.Printf("Warning: Failed to generate audio for news %s: %v", news.ID, err)
+		return false
+	}
+
+	news.AudioURL = audio.AudioURL
+	news.AudioKey = audio.AudioKey
+	news.CaptionsURL = audio.CaptionsURL
+	news.CaptionsKey = audio.CaptionsKey
+	news.AudioTimings = audio.Sentences
+	log.Printf("Audio generated successfully for news %s: %s", news.ID, audio.AudioURL)
+	return true
+}
+
+// startNewsJobHeartbeat bumps the job's updated_at every newsJobHeartbeatInterval, so other processes can tell
+// it is still running, and returns the function that stops it
+func startNewsJobHeartbeat(jobID string) func() {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(newsJobHeartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				updateNewsJob(jobID, bson.M{})
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// updateNewsJob sets fields on a job and bumps its updated_at
+func updateNewsJob(jobID string, set bson.M) {
+	jobsCollection := mongodb.GetCollection(newsJobsCollection)
+	if jobsCollection == nil {
+		return
+	}
+
+	set["updated_at"] = time.Now()
+	if _, err := jobsCollection.UpdateOne(context.Background(), bson.M{"_id": jobID}, bson.M{"$set": set}); err != nil {
+		log.Printf("Warning: Failed to update news job %s: %v", jobID, err)
+	}
+}
+
+// updateNewsJobArticle replaces the progress entry of one article in a job
+func updateNewsJobArticle(jobID string, index int, article model.NewsJobArticle) {
+	updateNewsJob(jobID, bson.M{fmt.Sprintf("articles.%d", index): article})
+}
+
+// failNewsJobArticle records a failed article and notifies listeners
+func failNewsJobArticle(jobID string, index int, article model.NewsJobArticle) {
+	article.Status = model.NewsArticleFailed
+	updateNewsJobArticle(jobID, index, article)
+	jobEvents.publish(NewsJobEvent{Type: NewsEventArticleFailed, JobID: jobID, Index: index, Error: article.Error})
+}
+
+// finishNewsJob marks a job as completed or failed and notifies listeners
+func finishNewsJob(jobID, status, errMsg string) {
+	set := bson.M{
+		"status":      status,
+		"finished_at": time.Now(),
+	}
+	if errMsg != "" {
+		set["error"] = errMsg
+	}
+	updateNewsJob(jobID, set)
+
+	eventType := NewsEventJobCompleted
+	if status == model.NewsJobFailed {
+		eventType = NewsEventJobFailed
+	}
+	jobEvents.publish(NewsJobEvent{Type: eventType, JobID: jobID, Index: -1, Error: errMsg})
+}
+
+// FailInterruptedNewsJobs marks jobs left pending or running by a stopped process as failed, so clients stop
+// polling them and users can start a new job. Jobs of other running instances keep heartbeating and are left alone;
+// jobs this process left behind before a quick restart are failed when their user next needs a job.
+func FailInterruptedNewsJobs() error {
+	return failStaleNewsJobs(bson.M{})
+}
+
+// failStaleNewsJobs fails the pending and running jobs matching filter that have not been updated within
+// newsJobStaleAfter
+func failStaleNewsJobs(filter bson.M) error {
+	jobsCollection := mongodb.GetCollection(newsJobsCollection)
+	if jobsCollection == nil {
+		return mongo.ErrClientDisconnected
+	}
+
+	now := time.Now()
+	filter["status"] = bson.M{"$in": []string{model.NewsJobPending, model.NewsJobRunning}}
+	filter["updated_at"] = bson.M{"$lt": now.Add(-newsJobStaleAfter)}
+	_, err := jobsCollection.UpdateMany(
+		context.Background(),
+		filter,
+		bson.M{"$set": bson.M{
+			"status":      model.NewsJobFailed,
+			"error":       "Interrupted because the server running it stopped",
+			"finished_at": now,
+			"updated_at":  now,
+		}},
+	)
+	return err
+}
+
+// GetNewsJob reports the progress of a news generation job
+func GetNewsJob(c echo.Context) error {
+	// Get user info from context
+	userID, _ := middleware.GetUserFromContext(c)
+	if userID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "User not authenticated",
+		})
+	}
+
+	jobID := c.Param("id")
+	if jobID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Job ID is required",
+		})
+	}
+
+	jobsCollection := mongodb.GetCollection(newsJobsCollection)
+	if jobsCollection == nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Database connection error",
+		})
+	}
+
+	var job model.NewsGenerationJob
+	err := jobsCollection.FindOne(context.Background(), bson.M{
+		"_id":     jobID,
+		"user_id": userID,
+	}).Decode(&job)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Job not found",
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Database error",
+		})
+	}
+
+	// Tell pollers about a job whose process stopped instead of leaving them waiting
+	if !job.IsFinished() && time.Since(job.UpdatedAt) > newsJobStaleAfter {
+		if err := failStaleNewsJobs(bson.M{"_id": job.ID}); err == nil {
+			jobsCollection.FindOne(context.Background(), bson.M{"_id": job.ID}).Decode(&job)
+		}
+	}
+
+	return c.JSON(http.StatusOK, NewsJobResponse{
+		Job: job,
+	})
+}
@@ -0,0 +1,191 @@
+package news
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"google-devjam-backend/model"
+	"google-devjam-backend/utils/middleware"
+	"google-devjam-backend/utils/mongodb"
+	"google-devjam-backend/utils/services"
+)
+
+type UpdateReadProgressRequest struct {
+	Progress float64 `json:"progress"` // 0 to 1
+}
+
+// DeleteNews permanently deletes an article and its audio
+func DeleteNews(c echo.Context) error {
+	// Get user info from context
+	userID, _ := middleware.GetUserFromContext(c)
+	if userID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "User not authenticated",
+		})
+	}
+
+	newsID := c.Param("id")
+	if newsID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "News ID is required",
+		})
+	}
+
+	newsCollection := mongodb.GetCollection("news")
+	if newsCollection == nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Database connection error",
+		})
+	}
+
+	var news model.News
+	err := newsCollection.FindOneAndDelete(context.Background(), bson.M{
+		"_id":     newsID,
+		"user_id": userID,
+	}).Decode(&news)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "News article not found",
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to delete news",
+		})
+	}
+
+	// The article is gone either way, so a failed audio delete is only logged.
+	// Pooled articles share their audio with other users, so the pool's cleanup removes it instead.
+	if news.PoolID == "" && (news.AudioKey != "" || news.CaptionsKey != "") {
+		if audioService, err := services.SharedAudioService(); err != nil {
+			log.Printf("Warning: Failed to initialize audio service to delete audio of news %s: %v", news.ID, err)
+		} else {
+			for _, key := range []string{news.AudioKey, news.CaptionsKey} {
+				if key == "" {
+					continue
+				}
+				if err := audioService.DeleteAudio(key); err != nil {
+					log.Printf("Warning: Failed to delete audio %s of news %s: %v", key, news.ID, err)
+				}
+			}
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "News deleted successfully",
+	})
+}
+
+// ArchiveNews moves an article into the user's history
+func ArchiveNews(c echo.Context) error {
+	return updateNewsState(c, bson.M{"$set": bson.M{"archived_at": time.Now()}})
+}
+
+// UnarchiveNews brings an archived article back into the user's current news
+func UnarchiveNews(c echo.Context) error {
+	return updateNewsState(c, bson.M{"$unset": bson.M{"archived_at": ""}})
+}
+
+// FavoriteNews marks an article as a favorite
+func FavoriteNews(c echo.Context) error {
+	return updateNewsState(c, bson.M{"$set": bson.M{"favorited_at": time.Now()}})
+}
+
+// UnfavoriteNews removes an article from the user's favorites
+func UnfavoriteNews(c echo.Context) error {
+	return updateNewsState(c, bson.M{"$unset": bson.M{"favorited_at": ""}})
+}
+
+// MarkNewsRead marks an article as fully read
+func MarkNewsRead(c echo.Context) error {
+	return updateNewsState(c, bson.M{"$set": bson.M{"read_at": time.Now(), "read_progress": 1.0}})
+}
+
+// MarkNewsUnread clears an article's read state and reading progress
+func MarkNewsUnread(c echo.Context) error {
+	return updateNewsState(c, bson.M{"$unset": bson.M{"read_at": "", "read_progress": ""}})
+}
+
+// UpdateReadProgress records how far the user has read through an article
+func UpdateReadProgress(c echo.Context) error {
+	var req UpdateReadProgressRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request format",
+		})
+	}
+
+	if req.Progress < 0 || req.Progress > 1 {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Progress must be between 0 and 1",
+		})
+	}
+
+	// Reading any part of an article counts as opening it, so opened_at is set if it is missing
+	return updateNewsState(c, []bson.M{
+		{"$set": bson.M{
+			"read_progress": req.Progress,
+			"opened_at":     bson.M{"$ifNull": bson.A{"$opened_at", time.Now()}},
+		}},
+	})
+}
+
+// updateNewsState applies an update document or pipeline to one of the user's articles and responds with the updated article
+func updateNewsState(c echo.Context, update interface{}) error {
+	// Get user info from context
+	userID, _ := middleware.GetUserFromContext(c)
+	if userID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "User not authenticated",
+		})
+	}
+
+	newsID := c.Param("id")
+	if newsID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "News ID is required",
+		})
+	}
+
+	newsCollection := mongodb.GetCollection("news")
+	if newsCollection == nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Database connection error",
+		})
+	}
+
+	var news model.News
+	err := newsCollection.FindOneAndUpdate(
+		context.Background(),
+		bson.M{
+			"_id":     newsID,
+			"user_id": userID,
+		},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&news)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "News article not found",
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to update news",
+		})
+	}
+
+	// Clean audio URL to ensure it only contains the path
+	news.AudioURL = cleanAudioURL(news.AudioURL)
+
+	return c.JSON(http.StatusOK, GetSingleNewsResponse{
+		News: news,
+	})
+}
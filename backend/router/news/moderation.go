@@ -0,0 +1,154 @@
+package news
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"google-devjam-backend/model"
+	"google-devjam-backend/utils/encrypt"
+	"google-devjam-backend/utils/gemini"
+	"google-devjam-backend/utils/moderation"
+	"google-devjam-backend/utils/mongodb"
+)
+
+const (
+	quarantinedNewsCollection = "quarantined_news"
+
+	// maxArticleAttempts is how many times an article is written before the job gives up on it as unsafe
+	maxArticleAttempts = 3
+	// safetyClassifyTimeout bounds one Gemini safety classification
+	safetyClassifyTimeout = 30 * time.Second
+)
+
+var errArticleUnsafe = errors.New("article failed content safety checks")
+
+// llmModerationEnabled reports whether articles that pass the local checks are also classified by Gemini.
+// NEWS_MODERATION_LLM=on enables it; it costs one extra Gemini call per article.
+func llmModerationEnabled() bool {
+	return os.Getenv("NEWS_MODERATION_LLM") == "on"
+}
+
+// moderationText is the part of an article the content checks read
+func moderationText(news *model.News) string {
+	return news.Title + "\n\n" + news.Content
+}
+
+// writeSafeArticle writes one article, from the source if given, and checks it before it is stored.
+// An article that fails is quarantined and written again as a different story, up to maxArticleAttempts times;
+// then errArticleUnsafe is returned.
+func writeSafeArticle(userID string, newsReq gemini.NewsGenerationRequest, source *model.SourceArticle, knownWords map[string]bool, kidSafe bool) (*model.News, error) {
+	for attempt := 1; attempt <= maxArticleAttempts; attempt++ {
+		var news *model.News
+		var err error
+		if source != nil {
+			news, err = rewriteSourceArticle(userID, newsReq, *source, knownWords)
+		} else {
+			news, err = generateNewsArticle(userID, newsReq, knownWords)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		quarantined := moderateNews(news, kidSafe)
+		if quarantined == nil {
+			return news, nil
+		}
+		quarantineNews(quarantined, attempt)
+
+		// Retelling the same story or writing the same topic would likely fail again, so let Gemini pick another
+		source = nil
+		newsReq.Topic = ""
+		newsReq.ExistingTitles = append(append([]string{}, newsReq.ExistingTitles...), news.Title)
+	}
+	return nil, errArticleUnsafe
+}
+
+// moderateNews runs the local checks and, when enabled, the Gemini classifier over an article.
+// It returns nil if the article may be shown, or the quarantine record explaining why not.
+// A failed classification is logged and the local verdict stands, so an outage does not stop news,
+// except for kid-safe users: their articles must pass every enabled check, so it counts as a failure.
+func moderateNews(news *model.News, kidSafe bool) *model.QuarantinedNews {
+	quarantined, err := checkNewsSafety(news, kidSafe)
+	if err != nil {
+		if kidSafe {
+			log.Printf("Warning: Failed to classify kid safety of news %s, not showing it: %v", news.ID, err)
+			return newQuarantinedNews(news, "llm", []string{"unclassified"}, []string{"Safety classification failed: " + err.Error()}, kidSafe)
+		}
+		log.Printf("Warning: Failed to classify safety of news %s, using local checks only: %v", news.ID, err)
+	}
+	return quarantined
+}
+
+// passesKidSafeChecks reports whether an article passes every kid-safe check that is enabled.
+// As in moderateNews for kid-safe users, a failed classification counts as not passing.
+func passesKidSafeChecks(news *model.News) bool {
+	quarantined, err := checkNewsSafety(news, true)
+	if err != nil {
+		log.Printf("Warning: Failed to classify kid safety of news %s, not marking it kid-safe: %v", news.ID, err)
+		return false
+	}
+	return quarantined == nil
+}
+
+// checkNewsSafety runs the local checks and, when enabled, the Gemini classifier. It returns the quarantine
+// record of a failing article, or the classifier's error with the article passing the local checks.
+func checkNewsSafety(news *model.News, kidSafe bool) (*model.QuarantinedNews, error) {
+	result := moderation.Check(moderationText(news), kidSafe)
+	if !result.Allowed {
+		return newQuarantinedNews(news, "local", result.Categories, result.Reasons, kidSafe), nil
+	}
+
+	if !llmModerationEnabled() {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), safetyClassifyTimeout)
+	classification, err := gemini.ClassifyContentSafety(ctx, news.Title, news.Content, kidSafe)
+	cancel()
+	if err != nil {
+		return nil, err
+	}
+	if !classification.Safe {
+		return newQuarantinedNews(news, "llm", classification.Categories, []string{classification.Reason}, kidSafe), nil
+	}
+
+	return nil, nil
+}
+
+func newQuarantinedNews(news *model.News, checker string, categories, reasons []string, kidSafe bool) *model.QuarantinedNews {
+	return &model.QuarantinedNews{
+		UserID:     news.UserID,
+		News:       *news,
+		Checker:    checker,
+		Categories: categories,
+		Reasons:    reasons,
+		KidSafe:    kidSafe,
+		CreatedAt:  time.Now(),
+	}
+}
+
+// quarantineNews logs why an article was rejected and keeps it for review instead of showing it
+func quarantineNews(quarantined *model.QuarantinedNews, attempt int) {
+	log.Printf("Quarantined news %q for user %s on attempt %d/%d (%s check): %s",
+		quarantined.News.Title, quarantined.UserID, attempt, maxArticleAttempts, quarantined.Checker, strings.Join(quarantined.Reasons, "; "))
+
+	quarantineCollection := mongodb.GetCollection(quarantinedNewsCollection)
+	if quarantineCollection == nil {
+		return
+	}
+
+	id, err := encrypt.GenerateSnowflakeID()
+	if err != nil {
+		log.Printf("Warning: Failed to generate quarantine ID for news %s: %v", quarantined.News.ID, err)
+		return
+	}
+	quarantined.ID = id
+
+	if _, err := quarantineCollection.InsertOne(context.Background(), quarantined); err != nil {
+		log.Printf("Warning: Failed to store quarantined news %s: %v", quarantined.News.ID, err)
+	}
+}
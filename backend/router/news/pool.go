@@ -0,0 +1,531 @@
+package news
+
+import (
+	"context"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"google-devjam-backend/model"
+	"google-devjam-backend/utils/encrypt"
+	"google-devjam-backend/utils/mongodb"
+	"google-devjam-backend/utils/nlp"
+	"google-devjam-backend/utils/services"
+)
+
+const (
+	newsPoolCollection       = "news_pool"
+	poolDeliveriesCollection = "news_pool_deliveries"
+
+	defaultNewsPoolMaxAge = 72 * time.Hour
+	// poolLevelTolerance is how far a pooled article's level may be from the user's
+	poolLevelTolerance = 1
+	// poolMinWordMatches is how many of the user's due words a pooled article must use (fewer if the user has fewer)
+	poolMinWordMatches = 2
+	// poolCandidateLimit bounds how many recent pooled articles are scored for one user
+	poolCandidateLimit = 100
+)
+
+// newsProfile is what pooled articles are matched against
+type newsProfile struct {
+	level      int // 0 when the user has no preferences
+	interests  []string
+	words      []string // Words to learn and review
+	knownWords map[string]bool
+	kidSafe    bool // Only articles that pass the kid-safe checks may be served
+}
+
+// newNewsProfile builds a profile from what a news job loads for the user
+func newNewsProfile(preferences *model.UserPreferences, learnWords, reviewWords []string, knownWords map[string]bool) newsProfile {
+	profile := newsProfile{knownWords: knownWords}
+	if preferences != nil {
+		profile.level = preferences.Level
+		profile.interests = preferences.Interests
+		profile.kidSafe = preferences.KidSafe
+	}
+	profile.words = append(profile.words, learnWords...)
+	profile.words = append(profile.words, reviewWords...)
+	return profile
+}
+
+// newsPoolMaxAge returns how long pooled articles are served, since news goes stale.
+// NEWS_POOL_MAX_AGE (default 72h) sets it; "off" disables the pool and ok is false.
+func newsPoolMaxAge() (maxAge time.Duration, ok bool) {
+	if os.Getenv("NEWS_POOL_MAX_AGE") == "off" {
+		return 0, false
+	}
+	return durationFromEnv("NEWS_POOL_MAX_AGE", defaultNewsPoolMaxAge), true
+}
+
+// servePooledNews copies up to count fitting pooled articles into the user's news and returns them.
+// Used before starting a generation job, so users whose needs the pool covers get articles without waiting.
+func servePooledNews(userID string, count int) ([]model.News, error) {
+	if _, ok := newsPoolMaxAge(); !ok {
+		return nil, nil
+	}
+
+	newsCollection := mongodb.GetCollection("news")
+	if newsCollection == nil {
+		return nil, mongo.ErrClientDisconnected
+	}
+
+	preferences, err := getUserPreferences(userID)
+	if err != nil {
+		preferences = nil // Match on vocabulary alone
+	}
+	learnWords, reviewWords, err := getUserVocabularyForNews(userID)
+	if err != nil {
+		return nil, err
+	}
+	knownWords, err := getUserKnownWords(userID)
+	if err != nil {
+		knownWords = nil
+	}
+
+	var served []model.News
+	for _, news := range takePooledNews(userID, newNewsProfile(preferences, learnWords, reviewWords, knownWords), count) {
+		if _, err := newsCollection.InsertOne(context.Background(), news); err != nil {
+			return served, err
+		}
+		news.AudioURL = cleanAudioURL(news.AudioURL)
+		served = append(served, *news)
+	}
+
+	return served, nil
+}
+
+// takePooledNews picks up to count pooled articles the user has not received that fit their profile, best first,
+// and returns them as the user's news, not yet stored. Each pick is recorded as delivered to the user.
+// Pool errors only mean fewer hits, so they are logged and the caller generates the rest.
+func takePooledNews(userID string, profile newsProfile, count int) []*model.News {
+	maxAge, ok := newsPoolMaxAge()
+	if !ok || count <= 0 {
+		return nil
+	}
+
+	poolCollection := mongodb.GetCollection(newsPoolCollection)
+	deliveriesCollection := mongodb.GetCollection(poolDeliveriesCollection)
+	if poolCollection == nil || deliveriesCollection == nil {
+		return nil
+	}
+
+	cutoff := time.Now().Add(-maxAge)
+
+	// Pooled articles older than the cutoff are not served, so older deliveries do not matter
+	seen, err := deliveriesCollection.Distinct(context.Background(), "pool_id", bson.M{
+		"user_id":    userID,
+		"created_at": bson.M{"$gte": cutoff},
+	})
+	if err != nil {
+		log.Printf("Warning: Failed to get pooled articles delivered to user %s: %v", userID, err)
+		return nil
+	}
+
+	filter := bson.M{
+		"created_at": bson.M{"$gte": cutoff},
+		"_id":        bson.M{"$nin": seen},
+	}
+	if profile.kidSafe {
+		filter["kid_safe"] = true
+	}
+	if profile.level > 0 {
+		filter["level"] = bson.M{"$gte": profile.level - poolLevelTolerance, "$lte": profile.level + poolLevelTolerance}
+	}
+	// Articles must use some due words; single words can be checked by the database against the stored base forms
+	var singleWords []string
+	for _, word := range profile.words {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" && !strings.Contains(word, " ") {
+			singleWords = append(singleWords, word)
+		}
+	}
+	if len(singleWords) == len(profile.words) && len(singleWords) > 0 {
+		filter["vocabulary"] = bson.M{"$in": singleWords}
+	}
+
+	cursor, err := poolCollection.Find(
+		context.Background(),
+		filter,
+		options.Find().
+			SetSort(bson.D{{Key: "created_at", Value: -1}}).
+			SetLimit(poolCandidateLimit),
+	)
+	if err != nil {
+		log.Printf("Warning: Failed to search the news pool for user %s: %v", userID, err)
+		return nil
+	}
+	defer cursor.Close(context.Background())
+
+	var candidates []model.PooledArticle
+	if err := cursor.All(context.Background(), &candidates); err != nil {
+		log.Printf("Warning: Failed to read the news pool for user %s: %v", userID, err)
+		return nil
+	}
+
+	type scoredArticle struct {
+		article model.PooledArticle
+		score   int
+	}
+	var fitting []scoredArticle
+	for _, article := range candidates {
+		if score, ok := poolArticleFit(article, profile); ok {
+			fitting = append(fitting, scoredArticle{article: article, score: score})
+		}
+	}
+	// Candidates are newest first, so a stable sort keeps newer articles ahead on equal scores
+	sort.SliceStable(fitting, func(i, j int) bool {
+		return fitting[i].score > fitting[j].score
+	})
+
+	var taken []*model.News
+	for _, candidate := range fitting {
+		if len(taken) == count {
+			break
+		}
+
+		news, err := newsFromPool(userID, candidate.article, profile)
+		if err != nil {
+			log.Printf("Warning: Failed to copy pooled article %s for user %s: %v", candidate.article.ID, userID, err)
+			continue
+		}
+
+		// The unique delivery index makes this the claim: a concurrent request for the same user cannot take it too
+		if err := recordPoolDelivery(userID, candidate.article.ID, news.ID); err != nil {
+			if !mongo.IsDuplicateKeyError(err) {
+				log.Printf("Warning: Failed to record delivery of pooled article %s to user %s: %v", candidate.article.ID, userID, err)
+			}
+			continue
+		}
+
+		_, err = poolCollection.UpdateOne(context.Background(),
+			bson.M{"_id": candidate.article.ID},
+			bson.M{"$inc": bson.M{"served_count": 1}},
+		)
+		if err != nil {
+			log.Printf("Warning: Failed to count delivery of pooled article %s: %v", candidate.article.ID, err)
+		}
+
+		taken = append(taken, news)
+	}
+
+	if len(taken) > 0 {
+		log.Printf("Served %d pooled news articles to user %s", len(taken), userID)
+	}
+	return taken
+}
+
+// poolArticleFit reports whether a pooled article fits the profile well enough to serve, and how well.
+// It must be close to the user's level, be about one of their interests and use some of their due words.
+func poolArticleFit(article model.PooledArticle, profile newsProfile) (score int, ok bool) {
+	levelDiff := 0
+	if profile.level > 0 {
+		levelDiff = article.Level - profile.level
+		if levelDiff < 0 {
+			levelDiff = -levelDiff
+		}
+		if levelDiff > poolLevelTolerance {
+			return 0, false
+		}
+	}
+
+	interestMatches := 0
+	if len(profile.interests) > 0 {
+		topics := make(map[string]bool, len(article.Topics))
+		for _, topic := range article.Topics {
+			topics[topic] = true
+		}
+		mentioned := make(map[string]bool)
+		for _, match := range nlp.FindWordMatches(article.Title+". "+strings.Join(article.Keywords, ". "), profile.interests) {
+			mentioned[match.Word] = true
+		}
+		for _, interest := range profile.interests {
+			if topics[strings.ToLower(interest)] || mentioned[interest] {
+				interestMatches++
+			}
+		}
+		if interestMatches == 0 {
+			return 0, false
+		}
+	}
+
+	wordMatches := 0
+	if len(profile.words) > 0 {
+		used := make(map[string]bool)
+		for _, match := range nlp.FindWordMatches(article.Content, profile.words) {
+			used[match.Word] = true
+		}
+		wordMatches = len(used)
+
+		required := poolMinWordMatches
+		if len(profile.words) < required {
+			required = len(profile.words)
+		}
+		if wordMatches < required {
+			return 0, false
+		}
+	}
+
+	return wordMatches*2 + interestMatches - levelDiff, true
+}
+
+// newsFromPool builds the user's copy of a pooled article. Highlights, coverage and readability are computed
+// for this user; the text, questions and audio are shared.
+func newsFromPool(userID string, article model.PooledArticle, profile newsProfile) (*model.News, error) {
+	newsID, err := encrypt.GenerateSnowflakeID()
+	if err != nil {
+		return nil, err
+	}
+
+	highlightSpans, usedWords := computeHighlights(article.Content, profile.words, article.Keywords)
+	coverage, missingWords := measureVocabCoverage(article.Content, profile.words)
+	readability := nlp.MeasureReadability(article.Content, profile.knownWords)
+
+	now := time.Now()
+	return &model.News{
+		ID:             newsID,
+		UserID:         userID,
+		Title:          article.Title,
+		Content:        article.Content,
+		Level:          readability.Level,
+		Readability:    &readability,
+		Keywords:       article.Keywords,
+		WordInNews:     usedWords,
+		VocabCoverage:  coverage,
+		MissingWords:   missingWords,
+		Source:         article.Source,
+		Sources:        article.Sources,
+		SearchQueries:  article.SearchQueries,
+		Original:       article.Original,
+		Summary:        article.Summary,
+		HighlightSpans: highlightSpans,
+		Questions:      article.Questions,
+		AudioURL:       article.AudioURL,
+		CaptionsURL:    article.CaptionsURL,
+		AudioTimings:   article.AudioTimings,
+		PoolID:         article.ID,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}, nil
+}
+
+// addToPool shares a newly generated article with other users. The pool takes over the article's audio, so the
+// user's copy is marked as pooled and deleting it no longer deletes the audio.
+// interests are the generating user's interests, used to tag what the article is about. kidSafe means the article
+// already passed the kid-safe checks for its kid-safe author; other articles are checked before kid-safe users get them.
+func addToPool(news *model.News, interests []string, kidSafe bool) {
+	if _, ok := newsPoolMaxAge(); !ok {
+		return
+	}
+
+	poolCollection := mongodb.GetCollection(newsPoolCollection)
+	newsCollection := mongodb.GetCollection("news")
+	if poolCollection == nil || newsCollection == nil {
+		return
+	}
+
+	poolID, err := encrypt.GenerateSnowflakeID()
+	if err != nil {
+		log.Printf("Warning: Failed to generate pool ID for news %s: %v", news.ID, err)
+		return
+	}
+
+	// Level and readability are measured without the generating user's vocabulary, so they suit anyone
+	readability := nlp.MeasureReadability(news.Content, nil)
+
+	now := time.Now()
+	article := model.PooledArticle{
+		ID:            poolID,
+		Title:         news.Title,
+		Content:       news.Content,
+		Level:         readability.Level,
+		Readability:   &readability,
+		Keywords:      news.Keywords,
+		Topics:        articleTopics(news, interests),
+		Vocabulary:    vocabularyForms(news.Content),
+		Source:        news.Source,
+		Sources:       news.Sources,
+		SearchQueries: news.SearchQueries,
+		Original:      news.Original,
+		Summary:       news.Summary,
+		Questions:     news.Questions,
+		AudioURL:      news.AudioURL,
+		AudioKey:      news.AudioKey,
+		CaptionsURL:   news.CaptionsURL,
+		CaptionsKey:   news.CaptionsKey,
+		AudioTimings:  news.AudioTimings,
+		KidSafe:       kidSafe || passesKidSafeChecks(news),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if _, err := poolCollection.InsertOne(context.Background(), article); err != nil {
+		log.Printf("Warning: Failed to add news %s to the pool: %v", news.ID, err)
+		return
+	}
+
+	_, err = newsCollection.UpdateOne(context.Background(),
+		bson.M{"_id": news.ID},
+		bson.M{"$set": bson.M{"pool_id": poolID}},
+	)
+	if err != nil {
+		// The user's copy would still own the audio, so withdraw the article rather than share audio it may delete
+		log.Printf("Warning: Failed to mark news %s as pooled: %v", news.ID, err)
+		poolCollection.DeleteOne(context.Background(), bson.M{"_id": poolID})
+		return
+	}
+	news.PoolID = poolID
+
+	// The generating user already has the article
+	if err := recordPoolDelivery(news.UserID, poolID, news.ID); err != nil {
+		log.Printf("Warning: Failed to record delivery of pooled article %s to user %s: %v", poolID, news.UserID, err)
+	}
+}
+
+// recordPoolDelivery stores that the user received the pooled article as the given news
+func recordPoolDelivery(userID, poolID, newsID string) error {
+	deliveriesCollection := mongodb.GetCollection(poolDeliveriesCollection)
+	if deliveriesCollection == nil {
+		return mongo.ErrClientDisconnected
+	}
+
+	deliveryID, err := encrypt.GenerateSnowflakeID()
+	if err != nil {
+		return err
+	}
+
+	_, err = deliveriesCollection.InsertOne(context.Background(), model.PoolDelivery{
+		ID:        deliveryID,
+		UserID:    userID,
+		PoolID:    poolID,
+		NewsID:    newsID,
+		CreatedAt: time.Now(),
+	})
+	return err
+}
+
+// articleTopics returns the lowercased interests the article mentions in its title, keywords or content.
+// If it mentions none, it was still written for these interests, so all of them are used.
+func articleTopics(news *model.News, interests []string) []string {
+	text := news.Title + ". " + strings.Join(news.Keywords, ". ") + ". " + news.Content
+
+	mentioned := make(map[string]bool)
+	for _, match := range nlp.FindWordMatches(text, interests) {
+		mentioned[match.Word] = true
+	}
+
+	topics := []string{}
+	for _, interest := range interests {
+		if mentioned[interest] || len(mentioned) == 0 {
+			topics = append(topics, strings.ToLower(interest))
+		}
+	}
+	return topics
+}
+
+// vocabularyForms returns every base form each word of text could be an inflection of,
+// so a stored article can be matched against single vocabulary words by the database
+func vocabularyForms(text string) []string {
+	seen := make(map[string]bool)
+	forms := []string{}
+	for _, token := range nlp.Tokens(text) {
+		for _, form := range nlp.LemmaCandidates(token.Text) {
+			if !seen[form] {
+				seen[form] = true
+				forms = append(forms, form)
+			}
+		}
+	}
+	return forms
+}
+
+// cleanupPooledAudio deletes the audio of pooled articles created before the cutoff once no user's current news
+// and no news archived after the cutoff uses it, then clears the audio fields of the article and every copy
+func cleanupPooledAudio(cutoff time.Time) (int, error) {
+	poolCollection := mongodb.GetCollection(newsPoolCollection)
+	newsCollection := mongodb.GetCollection("news")
+	if poolCollection == nil || newsCollection == nil {
+		return 0, mongo.ErrClientDisconnected
+	}
+
+	cursor, err := poolCollection.Find(
+		context.Background(),
+		bson.M{
+			"created_at": bson.M{"$lt": cutoff},
+			"audio_key":  bson.M{"$exists": true, "$ne": ""},
+		},
+		options.Find().
+			SetProjection(bson.M{"_id": 1, "audio_key": 1, "captions_key": 1}).
+			SetLimit(audioCleanupBatchSize),
+	)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(context.Background())
+
+	var expired []model.PooledArticle
+	if err := cursor.All(context.Background(), &expired); err != nil {
+		return 0, err
+	}
+	if len(expired) == 0 {
+		return 0, nil
+	}
+
+	audioService, err := services.SharedAudioService()
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, article := range expired {
+		inUse, err := newsCollection.CountDocuments(context.Background(), bson.M{
+			"pool_id": article.ID,
+			"$or": []bson.M{
+				{"archived_at": bson.M{"$exists": false}},
+				{"archived_at": bson.M{"$gte": cutoff}},
+			},
+		}, options.Count().SetLimit(1))
+		if err != nil {
+			return removed, err
+		}
+		if inUse > 0 {
+			continue
+		}
+
+		failed := false
+		for _, key := range []string{article.AudioKey, article.CaptionsKey} {
+			if key == "" {
+				continue
+			}
+			if err := audioService.DeleteAudio(key); err != nil {
+				// Leave the fields in place so the next pass retries this file
+				log.Printf("Warning: Failed to delete audio %s of pooled article %s: %v", key, article.ID, err)
+				failed = true
+			}
+		}
+		if failed {
+			continue
+		}
+
+		unset := bson.M{"$unset": bson.M{
+			"audio_url":     "",
+			"audio_key":     "",
+			"captions_url":  "",
+			"captions_key":  "",
+			"audio_timings": "",
+		}}
+		if _, err := poolCollection.UpdateOne(context.Background(), bson.M{"_id": article.ID}, unset); err != nil {
+			return removed, err
+		}
+		if _, err := newsCollection.UpdateMany(context.Background(), bson.M{"pool_id": article.ID}, unset); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+
+	return removed, nil
+}
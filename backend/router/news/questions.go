@@ -0,0 +1,256 @@
+package news
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"google-devjam-backend/model"
+	"google-devjam-backend/utils/encrypt"
+	"google-devjam-backend/utils/gemini"
+	"google-devjam-backend/utils/middleware"
+	"google-devjam-backend/utils/mongodb"
+	"google-devjam-backend/utils/nlp"
+)
+
+const (
+	// questionsTimeout bounds question generation for one article
+	questionsTimeout = 60 * time.Second
+	// questionsAttempts is how often question generation is tried before the article is kept without questions
+	questionsAttempts = 2
+	// gradeAnswersTimeout bounds LLM grading of one submission's short answers
+	gradeAnswersTimeout = 30 * time.Second
+)
+
+type SubmitAnswer struct {
+	QuestionID string `json:"question_id"`
+	Answer     string `json:"answer"`
+}
+
+type SubmitAnswersRequest struct {
+	Answers []SubmitAnswer `json:"answers"`
+}
+
+type SubmitAnswersResponse struct {
+	Attempt model.NewsQuizAttempt `json:"attempt"`
+}
+
+// attachNewsQuestions generates the summary and questions for a stored article and saves them on it.
+// Questions are optional, so failures are logged and the article is kept without them.
+func attachNewsQuestions(news *model.News) bool {
+	var result *gemini.NewsQuestionsResult
+	var err error
+	for attempt := 1; attempt <= questionsAttempts; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), questionsTimeout)
+		result, err = gemini.GenerateNewsQuestions(ctx, news.Title, news.Content, news.WordInNews)
+		cancel()
+		if err == nil {
+			break
+		}
+		log.Printf("Warning: Failed to generate questions for news %s (attempt %d/%d): %v", news.ID, attempt, questionsAttempts, err)
+	}
+	if err != nil {
+		return false
+	}
+
+	questions := make([]model.NewsQuestion, len(result.Questions))
+	for i, q := range result.Questions {
+		questions[i] = model.NewsQuestion{
+			ID:          fmt.Sprintf("q%d", i+1),
+			Type:        q.Type,
+			Question:    q.Question,
+			Options:     q.Options,
+			Word:        q.Word,
+			Answer:      q.Answer,
+			Explanation: q.Explanation,
+		}
+	}
+
+	newsCollection := mongodb.GetCollection("news")
+	if newsCollection == nil {
+		return false
+	}
+
+	_, err = newsCollection.UpdateOne(context.Background(),
+		bson.M{"_id": news.ID},
+		bson.M{"$set": bson.M{
+			"summary":   result.Summary,
+			"questions": questions,
+		}},
+	)
+	if err != nil {
+		log.Printf("Warning: Failed to save questions for news %s: %v", news.ID, err)
+		return false
+	}
+
+	news.Summary = result.Summary
+	news.Questions = questions
+	return true
+}
+
+// SubmitNewsAnswers grades the user's answers to an article's questions and records the attempt.
+// Choice questions are checked locally; short answers are graded by the LLM.
+func SubmitNewsAnswers(c echo.Context) error {
+	// Get user info from context
+	userID, _ := middleware.GetUserFromContext(c)
+	if userID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "User not authenticated",
+		})
+	}
+
+	newsID := c.Param("id")
+	if newsID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "News ID is required",
+		})
+	}
+
+	var req SubmitAnswersRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request format",
+		})
+	}
+
+	if len(req.Answers) == 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "At least one answer is required",
+		})
+	}
+
+	newsCollection := mongodb.GetCollection("news")
+	attemptsCollection := mongodb.GetCollection("news_quiz_attempts")
+	if newsCollection == nil || attemptsCollection == nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Database connection error",
+		})
+	}
+
+	var news model.News
+	err := newsCollection.FindOne(context.Background(), bson.M{
+		"_id":     newsID,
+		"user_id": userID,
+	}).Decode(&news)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "News article not found",
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Database error",
+		})
+	}
+
+	if len(news.Questions) == 0 {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "This article has no questions",
+		})
+	}
+
+	questions := make(map[string]model.NewsQuestion, len(news.Questions))
+	for _, q := range news.Questions {
+		questions[q.ID] = q
+	}
+
+	// Step 1: Check choice questions locally and collect short answers for the LLM
+	results := make([]model.NewsAnswerResult, 0, len(req.Answers))
+	var shortAnswers []gemini.ShortAnswerItem
+	resultIndex := make(map[string]int) // Question ID to its position in results; repeated answers are ignored
+	for _, answer := range req.Answers {
+		q, ok := questions[answer.QuestionID]
+		if !ok {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Unknown question ID: " + answer.QuestionID,
+			})
+		}
+		if _, seen := resultIndex[q.ID]; seen {
+			continue
+		}
+
+		result := model.NewsAnswerResult{
+			QuestionID:  q.ID,
+			Answer:      strings.TrimSpace(answer.Answer),
+			Expected:    q.Answer,
+			Explanation: q.Explanation,
+		}
+
+		if q.Type == gemini.QuestionShortAnswer {
+			if result.Answer != "" {
+				shortAnswers = append(shortAnswers, gemini.ShortAnswerItem{
+					ID:              q.ID,
+					Question:        q.Question,
+					ReferenceAnswer: q.Answer,
+					Answer:          result.Answer,
+				})
+			}
+		} else {
+			result.Correct = nlp.NormalizeForComparison(result.Answer) == nlp.NormalizeForComparison(q.Answer)
+		}
+
+		resultIndex[q.ID] = len(results)
+		results = append(results, result)
+	}
+
+	// Step 2: Grade short answers in one LLM call
+	if len(shortAnswers) > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), gradeAnswersTimeout)
+		grades, err := gemini.GradeShortAnswers(ctx, news.Content, shortAnswers)
+		cancel()
+		if err != nil {
+			return c.JSON(http.StatusServiceUnavailable, map[string]string{
+				"error": "Failed to grade answers: " + err.Error(),
+			})
+		}
+
+		for _, grade := range grades {
+			if i, ok := resultIndex[grade.ID]; ok && questions[grade.ID].Type == gemini.QuestionShortAnswer {
+				results[i].Correct = grade.Correct
+				results[i].Feedback = grade.Feedback
+			}
+		}
+	}
+
+	// Step 3: Record the attempt
+	attemptID, err := encrypt.GenerateSnowflakeID()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to generate attempt ID",
+		})
+	}
+
+	correct := 0
+	for _, result := range results {
+		if result.Correct {
+			correct++
+		}
+	}
+
+	attempt := model.NewsQuizAttempt{
+		ID:        attemptID,
+		UserID:    userID,
+		NewsID:    news.ID,
+		Results:   results,
+		Correct:   correct,
+		Total:     len(news.Questions),
+		CreatedAt: time.Now(),
+	}
+
+	if _, err := attemptsCollection.InsertOne(context.Background(), attempt); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to save answers",
+		})
+	}
+
+	return c.JSON(http.StatusOK, SubmitAnswersResponse{
+		Attempt: attempt,
+	})
+}
@@ -0,0 +1,53 @@
+package news
+
+import (
+	"context"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"google-devjam-backend/utils/mongodb"
+)
+
+// getUserKnownWords returns the lowercase words in the user's vocabulary, used to measure how much of an article is new to them
+func getUserKnownWords(userID string) (map[string]bool, error) {
+	userWordsCollection := mongodb.GetCollection("user_words")
+	if userWordsCollection == nil {
+		return nil, mongo.ErrClientDisconnected
+	}
+
+	pipeline := []bson.M{
+		{"$match": bson.M{"user_id": userID}},
+		{
+			"$lookup": bson.M{
+				"from":         "words",
+				"localField":   "word_id",
+				"foreignField": "_id",
+				"as":           "word_data",
+			},
+		},
+		{"$unwind": "$word_data"},
+		{"$project": bson.M{"word": "$word_data.word"}},
+	}
+
+	cursor, err := userWordsCollection.Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	var results []struct {
+		Word string `bson:"word"`
+	}
+	if err := cursor.All(context.Background(), &results); err != nil {
+		return nil, err
+	}
+
+	knownWords := make(map[string]bool, len(results))
+	for _, result := range results {
+		knownWords[strings.ToLower(result.Word)] = true
+	}
+
+	return knownWords, nil
+}
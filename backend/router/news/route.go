@@ -17,4 +17,37 @@ func InitRoutes(e *echo.Echo) {
 	// News retrieval endpoints
 	newsGroup.GET("", GetNews)           // GET /news - Get news articles with pagination and filtering
 	newsGroup.GET("/:id", GetSingleNews) // GET /news/:id - Get specific news article
+
+	// Article lifecycle endpoints
+	newsGroup.DELETE("/:id", DeleteNews)               // DELETE /news/:id - Delete an article and its audio
+	newsGroup.POST("/:id/archive", ArchiveNews)        // POST /news/:id/archive - Move an article to history
+	newsGroup.DELETE("/:id/archive", UnarchiveNews)    // DELETE /news/:id/archive - Restore an archived article
+	newsGroup.POST("/:id/favorite", FavoriteNews)      // POST /news/:id/favorite - Mark an article as a favorite
+	newsGroup.DELETE("/:id/favorite", UnfavoriteNews)  // DELETE /news/:id/favorite - Remove an article from favorites
+	newsGroup.POST("/:id/read", MarkNewsRead)          // POST /news/:id/read - Mark an article as read
+	newsGroup.DELETE("/:id/read", MarkNewsUnread)      // DELETE /news/:id/read - Mark an article as unread
+	newsGroup.PUT("/:id/progress", UpdateReadProgress) // PUT /news/:id/progress - Save reading progress
+
+	// Generation job endpoints
+	newsGroup.GET("/jobs/:id", GetNewsJob)           // GET /news/jobs/:id - Get the progress of a news generation job
+	newsGroup.GET("/jobs/:id/events", StreamNewsJob) // GET /news/jobs/:id/events - Stream generation progress as Server-Sent Events
+
+	// Reading aid endpoints
+	newsGroup.GET("/:id/gloss", GetNewsGloss) // GET /news/:id/gloss - Get sentence translations and word glosses
+
+	// Practice endpoints
+	newsGroup.POST("/:id/answers", SubmitNewsAnswers) // POST /news/:id/answers - Grade answers to the article's questions
+
+	// Vocabulary endpoints
+	newsGroup.POST("/:id/words", AddWordFromNews) // POST /news/:id/words - Save a word from the article with its sentence
+
+	// Feed token endpoints
+	newsGroup.GET("/feed-token", GetFeedToken)       // GET /news/feed-token - Get the state of the user's feed token
+	newsGroup.POST("/feed-token", CreateFeedToken)   // POST /news/feed-token - Create a feed token, revoking the old one
+	newsGroup.DELETE("/feed-token", RevokeFeedToken) // DELETE /news/feed-token - Revoke the user's feed token
+
+	// Feeds are read by feed readers and podcast apps, which authenticate with the feed token in the URL
+	feedGroup := e.Group("/feeds")
+	feedGroup.GET("/:token/atom.xml", GetAtomFeed)       // GET /feeds/:token/atom.xml - Articles as an Atom feed
+	feedGroup.GET("/:token/podcast.xml", GetPodcastFeed) // GET /feeds/:token/podcast.xml - Article audio as a podcast feed
 }
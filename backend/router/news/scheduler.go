@@ -0,0 +1,197 @@
+package news
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"google-devjam-backend/utils/mongodb"
+)
+
+const (
+	defaultNewsBatchInterval     = 4 * time.Hour
+	defaultSchedulerInterval     = 30 * time.Minute
+	defaultSchedulerActiveWindow = 7 * 24 * time.Hour
+	defaultSchedulerConcurrency  = 2
+	// maxScheduledUnread is how many unopened articles a user may have before the scheduler stops adding more;
+	// a user who has not read the last batch is not waiting for the next one
+	maxScheduledUnread = 4
+)
+
+// newsBatchInterval is how often a user gets a new batch of articles, set by NEWS_BATCH_INTERVAL
+func newsBatchInterval() time.Duration {
+	return durationFromEnv("NEWS_BATCH_INTERVAL", defaultNewsBatchInterval)
+}
+
+// StartNewsScheduler pre-generates the next batch of news for recently active users in the background,
+// so fresh articles are already waiting when they open the app.
+//
+// It is configured with environment variables:
+//   - NEWS_SCHEDULER_INTERVAL: how often to look for users who are due a batch (default 30m, "off" disables it)
+//   - NEWS_SCHEDULER_ACTIVE_WINDOW: users idle for longer than this are skipped (default 168h)
+//   - NEWS_SCHEDULER_CONCURRENCY: how many users are generated for at once (default 2)
+func StartNewsScheduler() {
+	if os.Getenv("NEWS_SCHEDULER_INTERVAL") == "off" {
+		log.Println("News scheduler is disabled")
+		return
+	}
+
+	interval := durationFromEnv("NEWS_SCHEDULER_INTERVAL", defaultSchedulerInterval)
+	activeWindow := durationFromEnv("NEWS_SCHEDULER_ACTIVE_WINDOW", defaultSchedulerActiveWindow)
+
+	concurrency := defaultSchedulerConcurrency
+	if value := os.Getenv("NEWS_SCHEDULER_CONCURRENCY"); value != "" {
+		if n, err := strconv.Atoi(value); err == nil && n > 0 {
+			concurrency = n
+		} else {
+			log.Printf("Warning: Invalid NEWS_SCHEDULER_CONCURRENCY '%s', using %d", value, concurrency)
+		}
+	}
+
+	log.Printf("News scheduler started: every %s for users active within %s", interval, activeWindow)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			runScheduledNews(activeWindow, concurrency)
+			<-ticker.C
+		}
+	}()
+}
+
+// runScheduledNews starts a batch for every active user who is due one and waits for the batches to finish.
+// Users with maxScheduledUnread or more unopened articles are skipped until they read some.
+func runScheduledNews(activeWindow time.Duration, concurrency int) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Error: News scheduler panicked: %v", r)
+		}
+	}()
+
+	userIDs, err := getActiveUserIDs(time.Now().Add(-activeWindow))
+	if err != nil {
+		log.Printf("Warning: News scheduler failed to get active users: %v", err)
+		return
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	scheduled := 0
+
+	for _, userID := range userIDs {
+		unread, err := countUnreadNews(userID)
+		if err != nil {
+			log.Printf("Warning: News scheduler failed to count unread news for user %s: %v", userID, err)
+			continue
+		}
+		if unread >= maxScheduledUnread {
+			continue
+		}
+
+		// Plan and create under the user's lock so a request arriving now reuses this job instead of starting its own
+		unlock := lockUser(userID)
+		count, _, err := planNewsBatch(userID)
+		if err != nil || count == 0 {
+			unlock()
+			if err != nil {
+				log.Printf("Warning: News scheduler failed to check news for user %s: %v", userID, err)
+			}
+			continue
+		}
+		job, created, err := createNewsJob(userID, count, false, true)
+		unlock()
+		if err != nil {
+			log.Printf("Warning: News scheduler failed to create job for user %s: %v", userID, err)
+			continue
+		}
+		if !created {
+			continue // A job requested by the user is already running
+		}
+
+		scheduled++
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			runNewsJob(*job)
+		}()
+	}
+
+	wg.Wait()
+
+	if scheduled > 0 {
+		log.Printf("News scheduler generated batches for %d of %d active users", scheduled, len(userIDs))
+	}
+}
+
+// getActiveUserIDs returns the IDs of users who made an authenticated request since the given time
+func getActiveUserIDs(since time.Time) ([]string, error) {
+	usersCollection := mongodb.GetCollection("users")
+	if usersCollection == nil {
+		return nil, mongo.ErrClientDisconnected
+	}
+
+	cursor, err := usersCollection.Find(
+		context.Background(),
+		bson.M{"last_active_at": bson.M{"$gte": since}},
+		options.Find().SetProjection(bson.M{"_id": 1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	var users []struct {
+		ID string `bson:"_id"`
+	}
+	if err := cursor.All(context.Background(), &users); err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]string, len(users))
+	for i, user := range users {
+		userIDs[i] = user.ID
+	}
+
+	return userIDs, nil
+}
+
+// countUnreadNews returns how many of the user's current articles have never been opened
+func countUnreadNews(userID string) (int64, error) {
+	newsCollection := mongodb.GetCollection("news")
+	if newsCollection == nil {
+		return 0, mongo.ErrClientDisconnected
+	}
+
+	return newsCollection.CountDocuments(context.Background(), bson.M{
+		"user_id":     userID,
+		"archived_at": bson.M{"$exists": false},
+		"opened_at":   bson.M{"$exists": false},
+	}, options.Count().SetLimit(maxScheduledUnread))
+}
+
+// durationFromEnv parses a duration such as "4h" or "30m" from the environment, falling back to the default
+func durationFromEnv(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		log.Printf("Warning: Invalid %s '%s', using %s", key, value, defaultValue)
+		return defaultValue
+	}
+
+	return duration
+}
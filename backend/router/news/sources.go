@@ -0,0 +1,65 @@
+package news
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"google-devjam-backend/model"
+)
+
+// locateSourceClaims finds each source's claims in the article content and records where they are.
+// Whitespace differences are ignored, since the claims come from the escaped JSON answer. Claims the article no
+// longer contains, for example after a coverage repair reworded them, keep their text with End left at 0.
+func locateSourceClaims(content string, sources []model.NewsSource) []model.NewsSource {
+	normalized, runeOffsets := collapseWhitespace(content)
+
+	located := make([]model.NewsSource, len(sources))
+	for i, source := range sources {
+		located[i] = source
+		located[i].Claims = make([]model.NewsClaim, len(source.Claims))
+		for j, claim := range source.Claims {
+			claim.Start, claim.End = 0, 0
+			needle, _ := collapseWhitespace(claim.Text)
+			if needle == "" {
+				located[i].Claims[j] = claim
+				continue
+			}
+			if at := strings.Index(normalized, needle); at >= 0 {
+				start := utf8.RuneCountInString(normalized[:at])
+				end := start + utf8.RuneCountInString(needle)
+				claim.Start = runeOffsets[start]
+				claim.End = runeOffsets[end-1] + 1
+			}
+			located[i].Claims[j] = claim
+		}
+	}
+
+	return located
+}
+
+// collapseWhitespace trims text and replaces each run of whitespace with one space. It also returns, for every
+// rune of the result, the offset of the rune it came from in text.
+func collapseWhitespace(text string) (string, []int) {
+	var b strings.Builder
+	var offsets []int
+
+	pendingSpace := -1
+	for i, r := range []rune(text) {
+		if unicode.IsSpace(r) {
+			if pendingSpace < 0 {
+				pendingSpace = i
+			}
+			continue
+		}
+		if pendingSpace >= 0 && b.Len() > 0 {
+			b.WriteRune(' ')
+			offsets = append(offsets, pendingSpace)
+		}
+		pendingSpace = -1
+		b.WriteRune(r)
+		offsets = append(offsets, i)
+	}
+
+	return b.String(), offsets
+}
@@ -0,0 +1,142 @@
+package news
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"google-devjam-backend/model"
+	"google-devjam-backend/router/vocabulary"
+	"google-devjam-backend/utils/middleware"
+	"google-devjam-backend/utils/mongodb"
+	"google-devjam-backend/utils/nlp"
+)
+
+type AddWordFromNewsRequest struct {
+	Word  string `json:"word" validate:"required"`
+	Start int    `json:"start"` // Character offset of the selection in the article content
+	End   int    `json:"end"`   // Exclusive end offset of the selection
+}
+
+type AddWordFromNewsResponse struct {
+	Word         model.Word        `json:"word"`
+	Example      model.WordExample `json:"example"`
+	AlreadySaved bool              `json:"already_saved"`
+}
+
+// AddWordFromNews saves a word selected in an article, keeping the article sentence as a personal example
+func AddWordFromNews(c echo.Context) error {
+	// Get user info from context
+	userID, _ := middleware.GetUserFromContext(c)
+	if userID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "User not authenticated",
+		})
+	}
+
+	newsID := c.Param("id")
+	if newsID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "News ID is required",
+		})
+	}
+
+	var req AddWordFromNewsRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid request format",
+		})
+	}
+
+	if strings.TrimSpace(req.Word) == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Word is required",
+		})
+	}
+
+	// Get news collection
+	newsCollection := mongodb.GetCollection("news")
+	if newsCollection == nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Database connection error",
+		})
+	}
+
+	var news model.News
+	err := newsCollection.FindOne(context.Background(), bson.M{
+		"_id":     newsID,
+		"user_id": userID,
+	}).Decode(&news)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "News article not found",
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Database error",
+		})
+	}
+
+	// Make sure the offsets actually point at the selected word
+	content := []rune(news.Content)
+	if req.Start < 0 || req.End <= req.Start || req.End > len(content) {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Selection offsets are out of range",
+		})
+	}
+	if !strings.EqualFold(strings.TrimSpace(string(content[req.Start:req.End])), strings.TrimSpace(req.Word)) {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Selection offsets do not match the selected word",
+		})
+	}
+
+	sentence, ok := nlp.SentenceAt(news.Content, req.Start)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Could not find the sentence containing the selected word",
+		})
+	}
+
+	// Add the base form through the normal vocabulary path
+	lemma := vocabulary.LemmatizeWord(req.Word)
+	alreadySaved := false
+	word, err := vocabulary.AddWordToVocabulary(userID, lemma)
+	if err != nil {
+		addErr, isAddErr := err.(*vocabulary.AddWordError)
+		if !isAddErr {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to add word to vocabulary",
+			})
+		}
+		if addErr.Status != http.StatusConflict || word == nil {
+			return c.JSON(addErr.Status, map[string]string{
+				"error": addErr.Message,
+			})
+		}
+		// The word is already saved; still remember where the user met it this time
+		alreadySaved = true
+	}
+
+	example, err := vocabulary.AddPersonalExample(userID, word.ID, news.ID, sentence.Text)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to save example sentence",
+		})
+	}
+
+	status := http.StatusCreated
+	if alreadySaved {
+		status = http.StatusOK
+	}
+
+	return c.JSON(status, AddWordFromNewsResponse{
+		Word:         *word,
+		Example:      *example,
+		AlreadySaved: alreadySaved,
+	})
+}
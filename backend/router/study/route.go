@@ -0,0 +1,14 @@
+package study
+
+import (
+	"google-devjam-backend/utils/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+func InitRoutes(e *echo.Echo) {
+	// All study routes require authentication
+	studyGroup := e.Group("/study", middleware.JWTMiddleware())
+
+	studyGroup.GET("/today", GetTodayPlan) // GET /study/today - Get today's study plan and progress
+}
@@ -0,0 +1,181 @@
+package study
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"google-devjam-backend/model"
+	"google-devjam-backend/router/vocabulary"
+	"google-devjam-backend/utils/middleware"
+	"google-devjam-backend/utils/mongodb"
+)
+
+const (
+	// maxDueReviews caps the reviews in one day's plan so a long break does not produce an endless session
+	maxDueReviews = 50
+	// articleCandidates is how many recent unread articles are considered for the plan
+	articleCandidates = 50
+)
+
+type DailyProgress struct {
+	Goal          int     `json:"goal"`
+	Completed     int     `json:"completed"`
+	Remaining     int     `json:"remaining"`
+	Percent       float64 `json:"percent"`
+	NewWordsAdded int     `json:"new_words_added"`
+	NewWordsQuota int     `json:"new_words_quota"`
+}
+
+type StudyPlanResponse struct {
+	Date       string                        `json:"date"`
+	DueReviews []vocabulary.WordWithUserData `json:"due_reviews"`
+	NewWords   []vocabulary.WordWithUserData `json:"new_words"`
+	Article    *model.News                   `json:"article"`
+	Progress   DailyProgress                 `json:"progress"`
+}
+
+// GetTodayPlan assembles the user's study session for today: due reviews, new words and one unread article
+func GetTodayPlan(c echo.Context) error {
+	// Get user info from context
+	userID, _ := middleware.GetUserFromContext(c)
+	if userID == "" {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "User not authenticated",
+		})
+	}
+
+	preferences, err := getUserPreferences(userID)
+	if err != nil {
+		// Continue with default goals if preferences are not set
+		preferences = nil
+	}
+
+	// Step 1: Words due for practice according to the forgetting curve
+	dueReviews, err := vocabulary.GetDueWords(userID, maxDueReviews)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to get due words: " + err.Error(),
+		})
+	}
+
+	// Step 2: Progress toward today's goal
+	practiced, added, err := vocabulary.CountPracticedToday(userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to get today's progress: " + err.Error(),
+		})
+	}
+
+	goal := preferences.GetDailyGoal()
+	progress := DailyProgress{
+		Goal:          goal,
+		Completed:     int(practiced),
+		Remaining:     max(goal-int(practiced), 0),
+		Percent:       min(float64(practiced)/float64(goal)*100, 100),
+		NewWordsAdded: int(added),
+		NewWordsQuota: preferences.GetDailyNewWords(),
+	}
+
+	// Step 3: New words from pending recommendations, up to what is left of today's quota
+	newWords := []vocabulary.WordWithUserData{}
+	if remainingNew := progress.NewWordsQuota - progress.NewWordsAdded; remainingNew > 0 {
+		newWords, err = vocabulary.GetPendingRecommendations(userID, remainingNew)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to get recommended words: " + err.Error(),
+			})
+		}
+	}
+
+	// Step 4: The unread article that reinforces the most due words
+	article, err := pickArticle(userID, dueReviews)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to get article: " + err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, StudyPlanResponse{
+		Date:       time.Now().Format("2006-01-02"),
+		DueReviews: dueReviews,
+		NewWords:   newWords,
+		Article:    article,
+		Progress:   progress,
+	})
+}
+
+// pickArticle returns the recent unopened, unread article containing the most due words, preferring newer articles on ties
+func pickArticle(userID string, dueWords []vocabulary.WordWithUserData) (*model.News, error) {
+	newsCollection := mongodb.GetCollection("news")
+	if newsCollection == nil {
+		return nil, mongo.ErrClientDisconnected
+	}
+
+	cursor, err := newsCollection.Find(
+		context.Background(),
+		bson.M{
+			"user_id":     userID,
+			"read_at":     bson.M{"$exists": false},
+			"opened_at":   bson.M{"$exists": false},
+			"archived_at": bson.M{"$exists": false},
+		},
+		options.Find().
+			SetSort(bson.D{{Key: "created_at", Value: -1}}).
+			SetLimit(articleCandidates),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	var candidates []model.News
+	if err := cursor.All(context.Background(), &candidates); err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	due := make(map[string]bool, len(dueWords))
+	for _, word := range dueWords {
+		due[strings.ToLower(word.Word.Word)] = true
+	}
+
+	best, bestScore := 0, -1
+	for i, news := range candidates {
+		score := 0
+		for _, word := range news.WordInNews {
+			if due[strings.ToLower(word)] {
+				score++
+			}
+		}
+		if score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+
+	return &candidates[best], nil
+}
+
+// getUserPreferences retrieves user preferences
+func getUserPreferences(userID string) (*model.UserPreferences, error) {
+	preferencesCollection := mongodb.GetCollection("user_preferences")
+	if preferencesCollection == nil {
+		return nil, mongo.ErrClientDisconnected
+	}
+
+	var preferences model.UserPreferences
+	err := preferencesCollection.FindOne(context.Background(), bson.M{"user_id": userID}).Decode(&preferences)
+	if err != nil {
+		return nil, err
+	}
+
+	return &preferences, nil
+}
@@ -17,13 +17,19 @@ import (
 )
 
 type CreatePreferencesRequest struct {
-	Level     int      `json:"level" validate:"required"`
-	Interests []string `json:"interests"`
+	Level         int      `json:"level" validate:"required"`
+	Interests     []string `json:"interests"`
+	DailyGoal     int      `json:"daily_goal,omitempty"`
+	DailyNewWords int      `json:"daily_new_words,omitempty"`
+	KidSafe       bool     `json:"kid_safe,omitempty"`
 }
 
 type UpdatePreferencesRequest struct {
-	Level     *int     `json:"level,omitempty"`
-	Interests []string `json:"interests,omitempty"`
+	Level         *int     `json:"level,omitempty"`
+	Interests     []string `json:"interests,omitempty"`
+	DailyGoal     *int     `json:"daily_goal,omitempty"`
+	DailyNewWords *int     `json:"daily_new_words,omitempty"`
+	KidSafe       *bool    `json:"kid_safe,omitempty"`
 }
 
 type PreferencesResponse struct {
@@ -91,6 +97,18 @@ func CreatePreferences(c echo.Context) error {
 		})
 	}
 
+	// Validate daily study targets (0 means use the default)
+	if req.DailyGoal < 0 || req.DailyGoal > 200 {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Daily goal must be between 1 and 200",
+		})
+	}
+	if req.DailyNewWords < 0 || req.DailyNewWords > 50 {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Daily new words must be between 1 and 50",
+		})
+	}
+
 	// Clean up interests
 	var cleanInterests []string
 	for _, interest := range req.Interests {
@@ -132,12 +150,15 @@ func CreatePreferences(c echo.Context) error {
 	// Create preferences
 	now := time.Now()
 	preferences := model.UserPreferences{
-		ID:        preferencesID,
-		UserID:    userID,
-		Level:     req.Level,
-		Interests: cleanInterests,
-		CreatedAt: now,
-		UpdatedAt: now,
+		ID:            preferencesID,
+		UserID:        userID,
+		Level:         req.Level,
+		Interests:     cleanInterests,
+		DailyGoal:     req.DailyGoal,
+		DailyNewWords: req.DailyNewWords,
+		KidSafe:       req.KidSafe,
+		CreatedAt:     now,
+		UpdatedAt:     now,
 	}
 
 	// Insert into database
@@ -207,6 +228,29 @@ func UpdatePreferences(c echo.Context) error {
 		updateData["level"] = *req.Level
 	}
 
+	// Update daily study targets if provided
+	if req.DailyGoal != nil {
+		if *req.DailyGoal < 1 || *req.DailyGoal > 200 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Daily goal must be between 1 and 200",
+			})
+		}
+		updateData["daily_goal"] = *req.DailyGoal
+	}
+
+	if req.DailyNewWords != nil {
+		if *req.DailyNewWords < 1 || *req.DailyNewWords > 50 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Daily new words must be between 1 and 50",
+			})
+		}
+		updateData["daily_new_words"] = *req.DailyNewWords
+	}
+
+	if req.KidSafe != nil {
+		updateData["kid_safe"] = *req.KidSafe
+	}
+
 	// Update interests if provided
 	if req.Interests != nil {
 		var cleanInterests []string
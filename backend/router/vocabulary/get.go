@@ -38,14 +38,20 @@ func getStringFromBSON(data bson.M, key string) string {
 	return ""
 }
 
-// Helper function to fetch WordExample records for a word
-func getWordExamples(wordID string) ([]model.WordExample, error) {
+// GetWordExamples fetches WordExample records for a word, including the user's personal examples
+func GetWordExamples(wordID, userID string) ([]model.WordExample, error) {
 	wordExamplesCollection := mongodb.GetCollection("word_examples")
 	if wordExamplesCollection == nil {
 		return []model.WordExample{}, nil
 	}
 
-	cursor, err := wordExamplesCollection.Find(context.Background(), bson.M{"word_id": wordID})
+	cursor, err := wordExamplesCollection.Find(context.Background(), bson.M{
+		"word_id": wordID,
+		"$or": []bson.M{
+			{"user_id": bson.M{"$exists": false}},
+			{"user_id": userID},
+		},
+	})
 	if err != nil {
 		return []model.WordExample{}, err
 	}
@@ -202,7 +208,7 @@ func GetWords(c echo.Context) error {
 		wordID := getStringFromBSON(wordData, "_id")
 
 		// Fetch examples for this word
-		examples, _ := getWordExamples(wordID) // Ignore error, continue with empty examples
+		examples, _ := GetWordExamples(wordID, userID) // Ignore error, continue with empty examples
 
 		word := WordWithUserData{
 			Word: model.Word{
@@ -305,7 +311,7 @@ func GetWord(c echo.Context) error {
 	wordIDStr := getStringFromBSON(wordData, "_id")
 
 	// Fetch examples for this word
-	examples, _ := getWordExamples(wordIDStr) // Ignore error, continue with empty examples
+	examples, _ := GetWordExamples(wordIDStr, userID) // Ignore error, continue with empty examples
 
 	word := WordWithUserData{
 		Word: model.Word{
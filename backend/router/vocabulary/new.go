@@ -9,12 +9,14 @@ import (
 	"github.com/labstack/echo/v4"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"google-devjam-backend/model"
 	"google-devjam-backend/utils/encrypt"
 	"google-devjam-backend/utils/gemini"
 	"google-devjam-backend/utils/middleware"
 	"google-devjam-backend/utils/mongodb"
+	"google-devjam-backend/utils/nlp"
 )
 
 type CreateWordRequest struct {
@@ -25,8 +27,8 @@ type WordResponse struct {
 	Word model.Word `json:"word"`
 }
 
-// Helper function to create WordExample records
-func createWordExamples(wordID string, examples []string) error {
+// Helper function to create WordExample records as part of a vocabulary write
+func createWordExamples(tx *mongodb.Tx, wordID string, examples []string) error {
 	if len(examples) == 0 {
 		return nil
 	}
@@ -37,6 +39,7 @@ func createWordExamples(wordID string, examples []string) error {
 	}
 
 	var wordExamples []interface{}
+	var exampleIDs []string
 	for _, example := range examples {
 		if strings.TrimSpace(example) == "" {
 			continue
@@ -53,16 +56,119 @@ func createWordExamples(wordID string, examples []string) error {
 			Sentence: strings.TrimSpace(example),
 		}
 		wordExamples = append(wordExamples, wordExample)
+		exampleIDs = append(exampleIDs, exampleID)
 	}
 
-	if len(wordExamples) > 0 {
-		_, err := wordExamplesCollection.InsertMany(context.Background(), wordExamples)
+	if len(wordExamples) == 0 {
+		return nil
+	}
+
+	if _, err := wordExamplesCollection.InsertMany(tx.Context(), wordExamples); err != nil {
 		return err
 	}
+	tx.OnRollback(func(ctx context.Context) error {
+		_, err := wordExamplesCollection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": exampleIDs}})
+		return err
+	})
 
 	return nil
 }
 
+// upsertWord inserts a word into the global words collection unless one with the same spelling exists.
+// It returns the stored word, which may have been created concurrently by another request, and whether it was inserted.
+// Inside a transaction a failed write rolls the word back; otherwise the word is kept.
+func upsertWord(tx *mongodb.Tx, newWord model.Word) (*model.Word, bool, error) {
+	wordsCollection := mongodb.GetCollection("words")
+	if wordsCollection == nil {
+		return nil, false, mongo.ErrClientDisconnected
+	}
+
+	for attempt := 0; attempt < 2; attempt++ {
+		result, err := wordsCollection.UpdateOne(tx.Context(),
+			bson.M{"word": newWord.Word},
+			bson.M{"$setOnInsert": newWord},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			if mongo.IsDuplicateKeyError(err) && !tx.InTransaction() {
+				// Lost an upsert race against the unique index; the next attempt finds the winner.
+				// Inside a transaction the error has aborted it, so it is returned and the transaction retried.
+				continue
+			}
+			return nil, false, err
+		}
+		if result.UpsertedCount > 0 {
+			// No compensation: without transactions a concurrent request for the same spelling may already have
+			// linked its user_words row to this word. A word nobody links to is still a valid dictionary entry
+			// that the next request for it reuses, so leaving it is harmless while deleting it could orphan a row.
+			return &newWord, true, nil
+		}
+		break
+	}
+
+	var storedWord model.Word
+	if err := wordsCollection.FindOne(tx.Context(), bson.M{"word": newWord.Word}).Decode(&storedWord); err != nil {
+		return nil, false, err
+	}
+	return &storedWord, false, nil
+}
+
+// upsertUserWord adds a word to the user's vocabulary unless it is already there, reporting whether it was added
+func upsertUserWord(tx *mongodb.Tx, userID, wordID string) (bool, error) {
+	userWordsCollection := mongodb.GetCollection("user_words")
+	if userWordsCollection == nil {
+		return false, mongo.ErrClientDisconnected
+	}
+
+	userWordID, err := encrypt.GenerateSnowflakeID()
+	if err != nil {
+		return false, err
+	}
+
+	now := time.Now()
+	userWord := model.UserWord{
+		ID:         userWordID,
+		UserID:     userID,
+		WordID:     wordID,
+		LearnCount: 0,
+		Fluency:    0,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	result, err := userWordsCollection.UpdateOne(tx.Context(),
+		bson.M{"user_id": userID, "word_id": wordID},
+		bson.M{"$setOnInsert": userWord},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) && !tx.InTransaction() {
+			// A concurrent request added the same word first; in a transaction the retry finds it instead
+			return false, nil
+		}
+		return false, err
+	}
+	if result.UpsertedCount == 0 {
+		return false, nil
+	}
+
+	tx.OnRollback(func(ctx context.Context) error {
+		_, err := userWordsCollection.DeleteOne(ctx, bson.M{"_id": userWordID})
+		return err
+	})
+	return true, nil
+}
+
+// AddWordError describes why a word could not be added, along with the HTTP status to report
+type AddWordError struct {
+	Status  int
+	Message string
+}
+
+func (e *AddWordError) Error() string {
+	return e.Message
+}
+
 func CreateWord(c echo.Context) error {
 	var req CreateWordRequest
 	if err := c.Bind(&req); err != nil {
@@ -78,14 +184,6 @@ func CreateWord(c echo.Context) error {
 		})
 	}
 
-	// Clean and normalize the word
-	word := strings.TrimSpace(strings.ToLower(req.Word))
-	if word == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "Word cannot be empty",
-		})
-	}
-
 	// Get user info from context
 	userID, _ := middleware.GetUserFromContext(c)
 	if userID == "" {
@@ -94,109 +192,118 @@ func CreateWord(c echo.Context) error {
 		})
 	}
 
-	// Check if word already exists in global words collection
-	wordsCollection := mongodb.GetCollection("words")
-	if wordsCollection == nil {
+	word, err := AddWordToVocabulary(userID, req.Word)
+	if err != nil {
+		if addErr, ok := err.(*AddWordError); ok {
+			return c.JSON(addErr.Status, map[string]string{
+				"error": addErr.Message,
+			})
+		}
 		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Database connection error",
+			"error": "Failed to add word to vocabulary",
 		})
 	}
 
+	return c.JSON(http.StatusCreated, WordResponse{
+		Word: *word,
+	})
+}
+
+// LemmatizeWord reduces an inflected word to its base form, preferring forms already in the global words collection
+func LemmatizeWord(word string) string {
+	candidates := nlp.LemmaCandidates(word)
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	if wordsCollection := mongodb.GetCollection("words"); wordsCollection != nil {
+		// Try the reduced forms before the surface form, so a stored "running" does not keep the inflection
+		for _, candidate := range append(candidates[1:], candidates[0]) {
+			var existing model.Word
+			if err := wordsCollection.FindOne(context.Background(), bson.M{"word": candidate}).Decode(&existing); err == nil {
+				return candidate
+			}
+		}
+	}
+
+	return nlp.Lemmatize(word)
+}
+
+// AddWordToVocabulary adds a word to the user's vocabulary, creating the global word through Gemini if needed.
+// When the user already has the word, the existing word is returned along with a 409 AddWordError.
+func AddWordToVocabulary(userID, rawWord string) (*model.Word, error) {
+	ctx := context.Background()
+
+	// Clean and normalize the word
+	word := strings.TrimSpace(strings.ToLower(rawWord))
+	if word == "" {
+		return nil, &AddWordError{Status: http.StatusBadRequest, Message: "Word cannot be empty"}
+	}
+
+	// Check if word already exists in global words collection
+	wordsCollection := mongodb.GetCollection("words")
+	userWordsCollection := mongodb.GetCollection("user_words")
+	if wordsCollection == nil || userWordsCollection == nil {
+		return nil, &AddWordError{Status: http.StatusInternalServerError, Message: "Database connection error"}
+	}
+
 	var existingWord model.Word
-	err := wordsCollection.FindOne(context.Background(), bson.M{"word": word}).Decode(&existingWord)
+	err := wordsCollection.FindOne(ctx, bson.M{"word": word}).Decode(&existingWord)
 	if err == nil {
 		// Word exists, check if user already has it
-		userWordsCollection := mongodb.GetCollection("user_words")
-		if userWordsCollection == nil {
-			return c.JSON(http.StatusInternalServerError, map[string]string{
-				"error": "Database connection error",
-			})
-		}
-
 		var existingUserWord model.UserWord
-		err = userWordsCollection.FindOne(context.Background(), bson.M{
+		err = userWordsCollection.FindOne(ctx, bson.M{
 			"user_id": userID,
 			"word_id": existingWord.ID,
 		}).Decode(&existingUserWord)
 
 		if err == nil {
-			return c.JSON(http.StatusConflict, map[string]string{
-				"error": "Word already exists in your vocabulary",
-			})
+			return &existingWord, &AddWordError{Status: http.StatusConflict, Message: "Word already exists in your vocabulary"}
 		} else if err != mongo.ErrNoDocuments {
-			return c.JSON(http.StatusInternalServerError, map[string]string{
-				"error": "Database error",
-			})
+			return nil, &AddWordError{Status: http.StatusInternalServerError, Message: "Database error"}
 		}
 
-		// Generate examples for existing word using Gemini
+		// Generate examples for existing word using Gemini, before any writes happen
 		translation, err := gemini.TranslateWord(word)
 		if err != nil {
 			// If Gemini fails, continue without examples
 			translation = &gemini.TranslationResult{Examples: []string{}}
 		}
 
-		// Add existing word to user's vocabulary
-		userWordID, err := encrypt.GenerateSnowflakeID()
-		if err != nil {
-			return c.JSON(http.StatusInternalServerError, map[string]string{
-				"error": "Failed to generate ID",
-			})
-		}
-
-		now := time.Now()
-		userWord := model.UserWord{
-			ID:         userWordID,
-			UserID:     userID,
-			WordID:     existingWord.ID,
-			LearnCount: 0,
-			Fluency:    0,
-			CreatedAt:  now,
-			UpdatedAt:  now,
-		}
-
-		_, err = userWordsCollection.InsertOne(context.Background(), userWord)
+		// Add existing word to user's vocabulary together with its examples
+		added := false
+		err = mongodb.WithTransaction(ctx, func(tx *mongodb.Tx) error {
+			var err error
+			added, err = upsertUserWord(tx, userID, existingWord.ID)
+			if err != nil || !added {
+				return err
+			}
+			return createWordExamples(tx, existingWord.ID, translation.Examples)
+		})
 		if err != nil {
-			return c.JSON(http.StatusInternalServerError, map[string]string{
-				"error": "Failed to add word to vocabulary",
-			})
+			return nil, &AddWordError{Status: http.StatusInternalServerError, Message: "Failed to add word to vocabulary"}
 		}
-
-		// Create WordExample records if available
-		if len(translation.Examples) > 0 {
-			if err := createWordExamples(existingWord.ID, translation.Examples); err != nil {
-				// Log error but don't fail the request
-				// Examples are not critical for word creation
-			}
+		if !added {
+			return &existingWord, &AddWordError{Status: http.StatusConflict, Message: "Word already exists in your vocabulary"}
 		}
 
-		return c.JSON(http.StatusCreated, WordResponse{
-			Word: existingWord,
-		})
+		return &existingWord, nil
 	} else if err != mongo.ErrNoDocuments {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Database error",
-		})
+		return nil, &AddWordError{Status: http.StatusInternalServerError, Message: "Database error"}
 	}
 
 	// Word doesn't exist, validate and translate using Gemini
 	translation, err := gemini.TranslateWord(word)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to validate word: " + err.Error(),
-		})
+		return nil, &AddWordError{Status: http.StatusInternalServerError, Message: "Failed to validate word: " + err.Error()}
 	}
 
 	if !translation.IsValid {
-		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "Invalid word: " + translation.Reason,
-		})
+		return nil, &AddWordError{Status: http.StatusBadRequest, Message: "Invalid word: " + translation.Reason}
 	}
 
 	if translation.DefinitionEn == "" || translation.DefinitionZh == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "Could not generate definition for this word",
-		})
+		return nil, &AddWordError{Status: http.StatusBadRequest, Message: "Could not generate definition for this word"}
 	}
 
 	// Use difficulty determined by Gemini
@@ -208,16 +315,7 @@ func CreateWord(c echo.Context) error {
 	// Generate IDs
 	wordID, err := encrypt.GenerateSnowflakeID()
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to generate word ID",
-		})
-	}
-
-	userWordID, err := encrypt.GenerateSnowflakeID()
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to generate user word ID",
-		})
+		return nil, &AddWordError{Status: http.StatusInternalServerError, Message: "Failed to generate word ID"}
 	}
 
 	// Create new word
@@ -235,48 +333,35 @@ func CreateWord(c echo.Context) error {
 		UpdatedAt:     now,
 	}
 
-	// Insert word into global words collection
-	_, err = wordsCollection.InsertOne(context.Background(), newWord)
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to create word",
-		})
-	}
-
-	// Add word to user's vocabulary
-	userWordsCollection := mongodb.GetCollection("user_words")
-	if userWordsCollection == nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Database connection error",
-		})
-	}
+	// Insert the word, the user's link to it and its examples as one unit
+	var storedWord *model.Word
+	added := false
+	err = mongodb.WithTransaction(ctx, func(tx *mongodb.Tx) error {
+		// Another request may have created the word meanwhile
+		var inserted bool
+		var err error
+		storedWord, inserted, err = upsertWord(tx, newWord)
+		if err != nil {
+			return err
+		}
 
-	userWord := model.UserWord{
-		ID:         userWordID,
-		UserID:     userID,
-		WordID:     wordID,
-		LearnCount: 0,
-		Fluency:    0,
-		CreatedAt:  now,
-		UpdatedAt:  now,
-	}
+		added, err = upsertUserWord(tx, userID, storedWord.ID)
+		if err != nil || !added {
+			return err
+		}
 
-	_, err = userWordsCollection.InsertOne(context.Background(), userWord)
+		// The request that created the word owns its examples
+		if !inserted {
+			return nil
+		}
+		return createWordExamples(tx, storedWord.ID, translation.Examples)
+	})
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to add word to vocabulary",
-		})
+		return nil, &AddWordError{Status: http.StatusInternalServerError, Message: "Failed to add word to vocabulary"}
 	}
-
-	// Create WordExample records if available
-	if len(translation.Examples) > 0 {
-		if err := createWordExamples(wordID, translation.Examples); err != nil {
-			// Log error but don't fail the request
-			// Examples are not critical for word creation
-		}
+	if !added {
+		return storedWord, &AddWordError{Status: http.StatusConflict, Message: "Word already exists in your vocabulary"}
 	}
 
-	return c.JSON(http.StatusCreated, WordResponse{
-		Word: newWord,
-	})
+	return storedWord, nil
 }
@@ -2,13 +2,16 @@ package vocabulary
 
 import (
 	"context"
+	"log"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/labstack/echo/v4"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"google-devjam-backend/model"
 	"google-devjam-backend/utils/encrypt"
@@ -17,6 +20,13 @@ import (
 	"google-devjam-backend/utils/mongodb"
 )
 
+const (
+	// recommendWorkerCount bounds how many recommended words are translated at once
+	recommendWorkerCount = 5
+	// recommendTranslateTimeout bounds each Gemini translation made for a recommended word
+	recommendTranslateTimeout = 30 * time.Second
+)
+
 type RecommendResponse struct {
 	Words []WordWithUserData `json:"words"`
 }
@@ -54,14 +64,33 @@ func GetRecommendations(c echo.Context) error {
 		})
 	}
 
-	// Step 3: Process each recommended word
+	// Step 4: Process the recommended words concurrently, keeping Gemini's order
+	processed := make([]*WordWithUserData, len(recommendations.Words))
+	semaphore := make(chan struct{}, recommendWorkerCount)
+	var wg sync.WaitGroup
+	for i, word := range recommendations.Words {
+		wg.Add(1)
+		go func(i int, word string) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			ctx, cancel := context.WithTimeout(c.Request().Context(), recommendTranslateTimeout)
+			defer cancel()
+
+			processedWord, err := processRecommendedWord(ctx, word, userID)
+			if err != nil {
+				// Log error but continue with other words
+				log.Printf("Warning: Failed to process recommended word '%s': %v", word, err)
+				return
+			}
+			processed[i] = processedWord
+		}(i, word)
+	}
+	wg.Wait()
+
 	var recommendedWords []WordWithUserData
-	for _, word := range recommendations.Words {
-		processedWord, err := processRecommendedWord(word, userID)
-		if err != nil {
-			// Log error but continue with other words
-			continue
-		}
+	for _, processedWord := range processed {
 		if processedWord != nil {
 			recommendedWords = append(recommendedWords, *processedWord)
 		}
@@ -124,7 +153,7 @@ func getUserWords(userID string) ([]string, error) {
 }
 
 // processRecommendedWord processes a single recommended word
-func processRecommendedWord(word, userID string) (*WordWithUserData, error) {
+func processRecommendedWord(ctx context.Context, word, userID string) (*WordWithUserData, error) {
 	// Clean and normalize the word
 	word = strings.TrimSpace(strings.ToLower(word))
 	if word == "" {
@@ -166,7 +195,7 @@ func processRecommendedWord(word, userID string) (*WordWithUserData, error) {
 		}
 
 		// Get examples for this word
-		examples, _ := getWordExamples(existingWord.ID)
+		examples, _ := GetWordExamples(existingWord.ID, userID)
 
 		// Return the existing word with user data
 		return &WordWithUserData{
@@ -180,7 +209,7 @@ func processRecommendedWord(word, userID string) (*WordWithUserData, error) {
 	}
 
 	// Word doesn't exist, translate using Gemini
-	translation, err := gemini.TranslateWord(word)
+	translation, err := gemini.TranslateWordWithContext(ctx, word)
 	if err != nil {
 		return nil, err
 	}
@@ -222,29 +251,31 @@ func processRecommendedWord(word, userID string) (*WordWithUserData, error) {
 		UpdatedAt:     now,
 	}
 
-	// Insert word into global words collection
-	_, err = wordsCollection.InsertOne(context.Background(), newWord)
+	// Insert the word and its examples as one unit; another request may have created it meanwhile
+	var storedWord *model.Word
+	err = mongodb.WithTransaction(ctx, func(tx *mongodb.Tx) error {
+		var inserted bool
+		var err error
+		storedWord, inserted, err = upsertWord(tx, newWord)
+		if err != nil || !inserted {
+			return err
+		}
+		return createWordExamples(tx, storedWord.ID, translation.Examples)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	// Create WordExample records if available
-	if len(translation.Examples) > 0 {
-		if err := createWordExamples(wordID, translation.Examples); err != nil {
-			// Log error but don't fail the request
-		}
-	}
-
 	// Add to RecommendWord collection
-	if err := addToRecommendWords(userID, wordID); err != nil {
+	if err := addToRecommendWords(userID, storedWord.ID); err != nil {
 		// Log error but continue
 	}
 
 	// Get examples for this word
-	examples, _ := getWordExamples(wordID)
+	examples, _ := GetWordExamples(storedWord.ID, userID)
 
 	return &WordWithUserData{
-		Word:       newWord,
+		Word:       *storedWord,
 		LearnCount: 0,
 		Fluency:    0,
 		Examples:   examples,
@@ -258,34 +289,28 @@ func addToRecommendWords(userID, wordID string) error {
 		return mongo.ErrClientDisconnected
 	}
 
-	// Check if already exists
-	var existing model.RecommendWord
-	err := recommendWordsCollection.FindOne(context.Background(), bson.M{
-		"user_id": userID,
-		"word_id": wordID,
-	}).Decode(&existing)
-
-	if err == nil {
-		// Already exists, skip
-		return nil
-	} else if err != mongo.ErrNoDocuments {
-		return err
-	}
-
 	// Generate ID
 	recommendID, err := encrypt.GenerateSnowflakeID()
 	if err != nil {
 		return err
 	}
 
-	// Create new recommend word record
+	// Create the recommend word record unless it already exists
 	recommendWord := model.RecommendWord{
 		ID:     recommendID,
 		UserID: userID,
 		WordID: wordID,
 	}
 
-	_, err = recommendWordsCollection.InsertOne(context.Background(), recommendWord)
+	_, err = recommendWordsCollection.UpdateOne(context.Background(),
+		bson.M{"user_id": userID, "word_id": wordID},
+		bson.M{"$setOnInsert": recommendWord},
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		// A concurrent request recorded the same recommendation
+		return nil
+	}
 	return err
 }
 
@@ -347,65 +372,32 @@ func AddRecommendedWordToLibrary(c echo.Context) error {
 		})
 	}
 
-	// Check if user already has this word in their library
-	userWordsCollection := mongodb.GetCollection("user_words")
-	if userWordsCollection == nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Database connection error",
-		})
-	}
-
-	var existingUserWord model.UserWord
-	err = userWordsCollection.FindOne(context.Background(), bson.M{
-		"user_id": userID,
-		"word_id": wordID,
-	}).Decode(&existingUserWord)
-
-	if err == nil {
-		return c.JSON(http.StatusConflict, map[string]string{
-			"error": "Word already exists in your library",
-		})
-	} else if err != mongo.ErrNoDocuments {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Database error",
-		})
-	}
+	// Move the word from recommendations into the user's library as one unit
+	added := false
+	err = mongodb.WithTransaction(context.Background(), func(tx *mongodb.Tx) error {
+		var err error
+		added, err = upsertUserWord(tx, userID, wordID)
+		if err != nil {
+			return err
+		}
 
-	// Generate user word ID
-	userWordID, err := encrypt.GenerateSnowflakeID()
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to generate ID",
+		// Remove from recommendations, even when the word was already in the library
+		_, err = recommendWordsCollection.DeleteOne(tx.Context(), bson.M{
+			"user_id": userID,
+			"word_id": wordID,
 		})
-	}
-
-	// Add word to user's library
-	now := time.Now()
-	userWord := model.UserWord{
-		ID:         userWordID,
-		UserID:     userID,
-		WordID:     wordID,
-		LearnCount: 0,
-		Fluency:    0,
-		CreatedAt:  now,
-		UpdatedAt:  now,
-	}
-
-	_, err = userWordsCollection.InsertOne(context.Background(), userWord)
+		// Last step, so a failure here only has the user word insert to undo
+		return err
+	})
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": "Failed to add word to library",
 		})
 	}
-
-	// Remove from recommendations
-	_, err = recommendWordsCollection.DeleteOne(context.Background(), bson.M{
-		"user_id": userID,
-		"word_id": wordID,
-	})
-	if err != nil {
-		// Log error but don't fail the request since the word was successfully added
-		// The recommendation will remain but that's not critical
+	if !added {
+		return c.JSON(http.StatusConflict, map[string]string{
+			"error": "Word already exists in your library",
+		})
 	}
 
 	return c.JSON(http.StatusCreated, map[string]string{
@@ -0,0 +1,194 @@
+package vocabulary
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"google-devjam-backend/model"
+	"google-devjam-backend/utils/mongodb"
+)
+
+// userWordWithData is a user_words document joined with its global word
+type userWordWithData struct {
+	model.UserWord `bson:",inline"`
+	WordData       model.Word `bson:"word_data"`
+}
+
+// NeedsLearning reports whether a word is still being learned rather than reviewed
+func NeedsLearning(learnCount, fluency int) bool {
+	return learnCount < 3 || fluency < 50
+}
+
+// ReviewIntervalDays returns how long a learned word can rest before it needs review, based on the forgetting curve.
+// The higher the fluency, the longer the interval before review.
+func ReviewIntervalDays(fluency int) int {
+	switch {
+	case fluency >= 90:
+		return 30 // Review after 30 days for high fluency
+	case fluency >= 70:
+		return 14 // Review after 14 days for medium-high fluency
+	case fluency >= 50:
+		return 7 // Review after 7 days for medium fluency
+	default:
+		return 3 // Review after 3 days for low fluency
+	}
+}
+
+// IsDueForReview reports whether a learned word has rested long enough to need review
+func IsDueForReview(fluency int, updatedAt, now time.Time) bool {
+	daysSinceUpdate := int(now.Sub(updatedAt).Hours() / 24)
+	return daysSinceUpdate >= ReviewIntervalDays(fluency)
+}
+
+// StartOfDay returns midnight of the given time's day in its location
+func StartOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// GetDueWords returns the user's words that need practice today: words still being learned that were not
+// practiced yet today, and learned words whose review interval has passed. Least fluent words come first.
+func GetDueWords(userID string, limit int) ([]WordWithUserData, error) {
+	userWordsCollection := mongodb.GetCollection("user_words")
+	if userWordsCollection == nil {
+		return nil, mongo.ErrClientDisconnected
+	}
+
+	pipeline := []bson.M{
+		{"$match": bson.M{"user_id": userID}},
+		{"$sort": bson.M{"fluency": 1, "updated_at": 1}},
+		{
+			"$lookup": bson.M{
+				"from":         "words",
+				"localField":   "word_id",
+				"foreignField": "_id",
+				"as":           "word_data",
+			},
+		},
+		{"$unwind": "$word_data"},
+	}
+
+	cursor, err := userWordsCollection.Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	var results []userWordWithData
+	if err := cursor.All(context.Background(), &results); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	today := StartOfDay(now)
+
+	dueWords := []WordWithUserData{}
+	for _, result := range results {
+		if limit > 0 && len(dueWords) >= limit {
+			break
+		}
+
+		due := false
+		if NeedsLearning(result.LearnCount, result.Fluency) {
+			// New words count as practiced once they were touched today
+			due = result.LearnCount == 0 || result.UpdatedAt.Before(today)
+		} else {
+			due = IsDueForReview(result.Fluency, result.UpdatedAt, now)
+		}
+		if !due {
+			continue
+		}
+
+		examples, _ := GetWordExamples(result.WordID, userID) // Ignore error, continue with empty examples
+		dueWords = append(dueWords, WordWithUserData{
+			Word:       result.WordData,
+			LearnCount: result.LearnCount,
+			Fluency:    result.Fluency,
+			Examples:   examples,
+		})
+	}
+
+	return dueWords, nil
+}
+
+// GetPendingRecommendations returns words recommended to the user that they have not added or dismissed yet
+func GetPendingRecommendations(userID string, limit int) ([]WordWithUserData, error) {
+	recommendWordsCollection := mongodb.GetCollection("recommend_words")
+	if recommendWordsCollection == nil {
+		return nil, mongo.ErrClientDisconnected
+	}
+
+	pipeline := []bson.M{
+		{"$match": bson.M{"user_id": userID}},
+		{
+			"$lookup": bson.M{
+				"from":         "words",
+				"localField":   "word_id",
+				"foreignField": "_id",
+				"as":           "word_data",
+			},
+		},
+		{"$unwind": "$word_data"},
+		// Easier words first, so a day's new words build on each other
+		{"$sort": bson.M{"word_data.difficulty": 1, "_id": 1}},
+	}
+	if limit > 0 {
+		pipeline = append(pipeline, bson.M{"$limit": limit})
+	}
+
+	cursor, err := recommendWordsCollection.Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	var results []struct {
+		WordData model.Word `bson:"word_data"`
+	}
+	if err := cursor.All(context.Background(), &results); err != nil {
+		return nil, err
+	}
+
+	words := make([]WordWithUserData, 0, len(results))
+	for _, result := range results {
+		examples, _ := GetWordExamples(result.WordData.ID, userID) // Ignore error, continue with empty examples
+		words = append(words, WordWithUserData{
+			Word:     result.WordData,
+			Examples: examples,
+		})
+	}
+
+	return words, nil
+}
+
+// CountPracticedToday counts the user's words that were practiced or added since midnight
+func CountPracticedToday(userID string) (practiced int64, added int64, err error) {
+	userWordsCollection := mongodb.GetCollection("user_words")
+	if userWordsCollection == nil {
+		return 0, 0, mongo.ErrClientDisconnected
+	}
+
+	today := StartOfDay(time.Now())
+
+	practiced, err = userWordsCollection.CountDocuments(context.Background(), bson.M{
+		"user_id":     userID,
+		"learn_count": bson.M{"$gt": 0},
+		"updated_at":  bson.M{"$gte": today},
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	added, err = userWordsCollection.CountDocuments(context.Background(), bson.M{
+		"user_id":    userID,
+		"created_at": bson.M{"$gte": today},
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return practiced, added, nil
+}
@@ -32,14 +32,20 @@ type AddExampleRequest struct {
 }
 
 // Helper function to update WordExample records for a word
-func updateWordExamples(wordID string, examples []string) error {
+func updateWordExamples(wordID, userID string, examples []string) error {
 	wordExamplesCollection := mongodb.GetCollection("word_examples")
 	if wordExamplesCollection == nil {
 		return mongo.ErrClientDisconnected
 	}
 
-	// Delete existing examples for this word
-	_, err := wordExamplesCollection.DeleteMany(context.Background(), bson.M{"word_id": wordID})
+	// Delete existing examples for this word, leaving other users' personal examples alone
+	_, err := wordExamplesCollection.DeleteMany(context.Background(), bson.M{
+		"word_id": wordID,
+		"$or": []bson.M{
+			{"user_id": bson.M{"$exists": false}},
+			{"user_id": userID},
+		},
+	})
 	if err != nil {
 		return err
 	}
@@ -169,7 +175,7 @@ func UpdateWord(c echo.Context) error {
 
 	// Update examples if provided
 	if req.Examples != nil {
-		if err := updateWordExamples(wordID, req.Examples); err != nil {
+		if err := updateWordExamples(wordID, userID, req.Examples); err != nil {
 			return c.JSON(http.StatusInternalServerError, map[string]string{
 				"error": "Failed to update word examples",
 			})
@@ -374,12 +380,32 @@ func LearnWord(c echo.Context) error {
 		})
 	}
 
+	learnCount, fluency, err := UpdateLearningProgress(userID, wordID, req.Correct)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Word not found in your vocabulary",
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to update learning progress",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message":     "Learning progress updated",
+		"learn_count": learnCount,
+		"fluency":     fluency,
+	})
+}
+
+// UpdateLearningProgress records one practice attempt on a word and returns the new learn count and fluency.
+// It returns mongo.ErrNoDocuments when the word is not in the user's vocabulary.
+func UpdateLearningProgress(userID, wordID string, correct bool) (int, int, error) {
 	// Get user words collection
 	userWordsCollection := mongodb.GetCollection("user_words")
 	if userWordsCollection == nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Database connection error",
-		})
+		return 0, 0, mongo.ErrClientDisconnected
 	}
 
 	// Find the user word
@@ -388,23 +414,15 @@ func LearnWord(c echo.Context) error {
 		"user_id": userID,
 		"word_id": wordID,
 	}).Decode(&userWord)
-
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return c.JSON(http.StatusNotFound, map[string]string{
-				"error": "Word not found in your vocabulary",
-			})
-		}
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Database error",
-		})
+		return 0, 0, err
 	}
 
 	// Update learning statistics
 	newLearnCount := userWord.LearnCount + 1
 	newFluency := userWord.Fluency
 
-	if req.Correct {
+	if correct {
 		// Increase fluency if correct (max 100)
 		newFluency += 10
 		if newFluency > 100 {
@@ -433,16 +451,51 @@ func LearnWord(c echo.Context) error {
 			},
 		},
 	)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return newLearnCount, newFluency, nil
+}
+
+// AddPersonalExample stores an example sentence that only the given user sees, optionally linked to a news article
+func AddPersonalExample(userID, wordID, newsID, sentence string) (*model.WordExample, error) {
+	wordExamplesCollection := mongodb.GetCollection("word_examples")
+	if wordExamplesCollection == nil {
+		return nil, mongo.ErrClientDisconnected
+	}
+
+	sentence = strings.TrimSpace(sentence)
+
+	// Saving the same word from the same sentence twice should not duplicate the example
+	var existing model.WordExample
+	err := wordExamplesCollection.FindOne(context.Background(), bson.M{
+		"word_id":  wordID,
+		"user_id":  userID,
+		"sentence": sentence,
+	}).Decode(&existing)
+	if err == nil {
+		return &existing, nil
+	} else if err != mongo.ErrNoDocuments {
+		return nil, err
+	}
 
+	exampleID, err := encrypt.GenerateSnowflakeID()
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to update learning progress",
-		})
+		return nil, err
 	}
 
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"message":     "Learning progress updated",
-		"learn_count": newLearnCount,
-		"fluency":     newFluency,
-	})
+	wordExample := model.WordExample{
+		ID:       exampleID,
+		WordID:   wordID,
+		Sentence: sentence,
+		UserID:   userID,
+		NewsID:   newsID,
+	}
+
+	if _, err := wordExamplesCollection.InsertOne(context.Background(), wordExample); err != nil {
+		return nil, err
+	}
+
+	return &wordExample, nil
 }
@@ -8,6 +8,8 @@ from flask import Flask, request, jsonify
 import os
 import uuid
 import re
+import io
+import base64
 import platform
 import subprocess
 import tempfile
@@ -328,6 +330,78 @@ def text_to_speech():
         traceback.print_exc()
         return jsonify({"error": str(e)}), 500
 
+# Silence inserted between sentences of a timed recording, in seconds
+SENTENCE_PAUSE_SECONDS = 0.25
+
+@app.route("/api/tts/timed", methods=["POST"])
+def timed_text_to_speech():
+    """Synthesize a list of sentences as one recording and report when each sentence is spoken.
+    Word timings are not reported, since the model does not expose them; the backend estimates them per sentence."""
+    try:
+        data = request.get_json()
+        sentences = data.get("sentences", [])
+        voice = data.get("voice", "")
+        speed = data.get("speed", 1.0)
+        
+        if not sentences or not all(isinstance(s, str) for s in sentences):
+            return jsonify({"error": "Sentences are required"}), 400
+        
+        available_voice_names = [v['name'] for v in available_voices]
+        if not voice or voice not in available_voice_names:
+            voice = default_voice
+        
+        speed = max(0.5, min(2.0, float(speed)))
+        
+        logger.info(f"🎭 Generating {len(sentences)} timed sentences with voice '{voice}' at {speed}x speed")
+        
+        # Sentences are synthesized one at a time, so their exact position in the recording is known
+        final_sample_rate = 22050
+        pause = np.zeros(int(SENTENCE_PAUSE_SECONDS * final_sample_rate))
+        chunks = []
+        timings = []
+        position = 0
+        for index, sentence in enumerate(sentences):
+            processed_text = preprocess_text(sentence)
+            if not processed_text:
+                timings.append({"index": index, "start": position / final_sample_rate, "end": position / final_sample_rate})
+                continue
+            
+            wav_data, sample_rate = tts_manager.synthesize_speech(processed_text, voice, speed)
+            enhanced_wav, _ = enhance_neural_audio(wav_data, sample_rate, final_sample_rate)
+            
+            start = position
+            chunks.append(enhanced_wav)
+            position += len(enhanced_wav)
+            timings.append({"index": index, "start": start / final_sample_rate, "end": position / final_sample_rate})
+            
+            if index < len(sentences) - 1:
+                chunks.append(pause)
+                position += len(pause)
+        
+        if not chunks:
+            return jsonify({"error": "Sentences contain no speakable text"}), 400
+        
+        buffer = io.BytesIO()
+        sf.write(buffer, np.concatenate(chunks), final_sample_rate, format='WAV', subtype='PCM_16')
+        filename = f"{uuid.uuid4()}.wav"
+        
+        logger.info(f"✅ Timed neural TTS audio generated: {filename} ({position / final_sample_rate:.1f}s)")
+        
+        return jsonify({
+            "audio": base64.b64encode(buffer.getvalue()).decode("ascii"),
+            "filename": filename,
+            "sample_rate": final_sample_rate,
+            "sentences": timings,
+            "voice": voice,
+            "model": tts_manager.current_model_name
+        })
+        
+    except Exception as e:
+        logger.error(f"❌ Error: {str(e)}")
+        import traceback
+        traceback.print_exc()
+        return jsonify({"error": str(e)}), 500
+
 @app.route("/health", methods=["GET"])
 def health():
     return jsonify({
@@ -341,7 +415,7 @@ def health():
         "default_voice": default_voice,
         "available_voices": [v['name'] for v in available_voices],
         "total_voices": len(available_voices),
-        "features": ["neural_synthesis", "multi_speaker", "speed_control", "audio_enhancement", "high_quality"],
+        "features": ["neural_synthesis", "multi_speaker", "speed_control", "audio_enhancement", "high_quality", "timing"],
         "python_version": sys.version.split()[0]
     })
 
@@ -0,0 +1,141 @@
+package feeds
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// minParagraphRunes is the shortest paragraph kept when extracting an article; shorter ones are usually captions,
+// bylines or buttons
+const minParagraphRunes = 40
+
+// skippedElements never contain article text
+var skippedElements = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Nav: true, atom.Header: true,
+	atom.Footer: true, atom.Aside: true, atom.Form: true, atom.Figure: true, atom.Button: true,
+}
+
+// ExtractArticle downloads a web page and returns the text of its article, one paragraph per line
+func (c *Client) ExtractArticle(ctx context.Context, pageURL string) (string, error) {
+	body, err := c.get(ctx, pageURL)
+	if err != nil {
+		return "", err
+	}
+	return ExtractArticleText(body)
+}
+
+// ExtractArticleText returns the paragraphs of an HTML page's main article, one per line.
+// Paragraphs inside <article> (or <main>) are preferred; otherwise every long enough paragraph on the page is used.
+func ExtractArticleText(page []byte) (string, error) {
+	doc, err := html.Parse(bytes.NewReader(page))
+	if err != nil {
+		return "", err
+	}
+
+	root := findElement(doc, atom.Article)
+	if root == nil {
+		root = findElement(doc, atom.Main)
+	}
+	if root == nil {
+		root = doc
+	}
+
+	var paragraphs []string
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			if skippedElements[n.DataAtom] {
+				return
+			}
+			if n.DataAtom == atom.P {
+				if text := collapseSpaces(nodeText(n)); len([]rune(text)) >= minParagraphRunes {
+					paragraphs = append(paragraphs, text)
+				}
+				return
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(root)
+
+	return strings.Join(paragraphs, "\n\n"), nil
+}
+
+// TextFromHTML reduces an HTML fragment, such as a feed summary, to plain text with paragraphs on separate lines
+func TextFromHTML(fragment string) string {
+	fragment = strings.TrimSpace(fragment)
+	if fragment == "" {
+		return ""
+	}
+
+	nodes, err := html.ParseFragment(strings.NewReader(fragment), &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body})
+	if err != nil {
+		return collapseSpaces(fragment)
+	}
+
+	var b strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch {
+		case n.Type == html.TextNode:
+			b.WriteString(n.Data)
+		case n.Type == html.ElementNode && skippedElements[n.DataAtom]:
+			return
+		case n.Type == html.ElementNode && (n.DataAtom == atom.P || n.DataAtom == atom.Br || n.DataAtom == atom.Div || n.DataAtom == atom.Li):
+			b.WriteString("\n")
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	for _, node := range nodes {
+		walk(node)
+	}
+
+	var paragraphs []string
+	for _, line := range strings.Split(b.String(), "\n") {
+		if line = collapseSpaces(line); line != "" {
+			paragraphs = append(paragraphs, line)
+		}
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// findElement returns the first element of the given type in document order
+func findElement(n *html.Node, element atom.Atom) *html.Node {
+	if n.Type == html.ElementNode && n.DataAtom == element {
+		return n
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if found := findElement(child, element); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// nodeText returns all text inside a node
+func nodeText(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var b strings.Builder
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && skippedElements[child.DataAtom] {
+			continue
+		}
+		b.WriteString(nodeText(child))
+	}
+	return b.String()
+}
+
+// collapseSpaces trims text and replaces each run of whitespace with one space
+func collapseSpaces(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,262 @@
+package feeds
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	// maxFeedBytes bounds how much of a feed or page is read
+	maxFeedBytes = 5 << 20
+	// maxRedirects bounds how many redirects one fetch follows
+	maxRedirects = 5
+	userAgent    = "devjam-news-ingest/1.0"
+)
+
+var errPrivateAddress = errors.New("address is loopback, private or link-local")
+
+// Item is one entry of an RSS or Atom feed
+type Item struct {
+	FeedTitle   string
+	Title       string
+	Link        string
+	GUID        string // Falls back to Link when the feed has no ID
+	PublishedAt time.Time
+	Summary     string // Plain text
+	Content     string // Plain text of the full content, when the feed includes it
+}
+
+// Client fetches feeds and article pages
+type Client struct {
+	HTTPClient *http.Client
+}
+
+// Config tunes a Client
+type Config struct {
+	// AllowPrivateAddresses lets the client reach loopback, private and link-local addresses. Feeds and the links
+	// in them are third-party input, so this is off by default to keep them from reaching internal services;
+	// turn it on only for a trusted local feed server, such as in tests.
+	AllowPrivateAddresses bool
+}
+
+// NewClient creates a feed client with a timeout suited to polling that only fetches public http(s) URLs
+func NewClient() *Client {
+	return NewClientWithConfig(Config{})
+}
+
+// NewClientWithConfig creates a feed client with a timeout suited to polling
+func NewClientWithConfig(config Config) *Client {
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	if !config.AllowPrivateAddresses {
+		// Checked on the resolved address at connect time, so DNS names and redirects cannot get around it
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || isPrivateIP(ip) {
+				return fmt.Errorf("refusing to connect to %s: %w", host, errPrivateAddress)
+			}
+			return nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	transport.Proxy = nil // A proxy would make the dialer check the proxy's address instead of the target's
+
+	return &Client{
+		HTTPClient: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: transport,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= maxRedirects {
+					return fmt.Errorf("stopped after %d redirects", maxRedirects)
+				}
+				return checkScheme(req.URL)
+			},
+		},
+	}
+}
+
+// isPrivateIP reports whether ip belongs to the host or a private network rather than the public internet
+func isPrivateIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified()
+}
+
+// checkScheme only allows plain web URLs, so feeds cannot point the client at files or other protocols
+func checkScheme(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported URL scheme %q", u.Scheme)
+	}
+	return nil
+}
+
+type rssDocument struct {
+	Channel struct {
+		Title string    `xml:"title"`
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+	Items []rssItem `xml:"item"` // RSS 1.0 puts items next to the channel
+}
+
+type rssItem struct {
+	Title          string `xml:"title"`
+	Link           string `xml:"link"`
+	GUID           string `xml:"guid"`
+	PubDate        string `xml:"pubDate"`
+	Date           string `xml:"http://purl.org/dc/elements/1.1/ date"`
+	Description    string `xml:"description"`
+	ContentEncoded string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+}
+
+type atomDocument struct {
+	Title   string      `xml:"title"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	Title     string `xml:"title"`
+	ID        string `xml:"id"`
+	Published string `xml:"published"`
+	Updated   string `xml:"updated"`
+	Summary   string `xml:"summary"`
+	Content   string `xml:"content"`
+	Links     []struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr"`
+	} `xml:"link"`
+}
+
+// Fetch downloads and parses an RSS 2.0, RSS 1.0 or Atom feed
+func (c *Client) Fetch(ctx context.Context, feedURL string) ([]Item, error) {
+	body, err := c.get(ctx, feedURL)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(body)
+}
+
+// Parse reads the items of an RSS 2.0, RSS 1.0 or Atom document. HTML in summaries and content is reduced to text.
+func Parse(data []byte) ([]Item, error) {
+	var root struct {
+		XMLName xml.Name
+	}
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse feed: %v", err)
+	}
+
+	var items []Item
+	switch strings.ToLower(root.XMLName.Local) {
+	case "rss", "rdf":
+		var doc rssDocument
+		if err := xml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse RSS feed: %v", err)
+		}
+		for _, entry := range append(doc.Channel.Items, doc.Items...) {
+			item := Item{
+				FeedTitle:   strings.TrimSpace(doc.Channel.Title),
+				Title:       strings.TrimSpace(entry.Title),
+				Link:        strings.TrimSpace(entry.Link),
+				GUID:        strings.TrimSpace(entry.GUID),
+				PublishedAt: parseFeedTime(entry.PubDate, entry.Date),
+				Summary:     TextFromHTML(entry.Description),
+				Content:     TextFromHTML(entry.ContentEncoded),
+			}
+			items = append(items, item)
+		}
+	case "feed":
+		var doc atomDocument
+		if err := xml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse Atom feed: %v", err)
+		}
+		for _, entry := range doc.Entries {
+			item := Item{
+				FeedTitle:   strings.TrimSpace(doc.Title),
+				Title:       strings.TrimSpace(entry.Title),
+				GUID:        strings.TrimSpace(entry.ID),
+				PublishedAt: parseFeedTime(entry.Published, entry.Updated),
+				Summary:     TextFromHTML(entry.Summary),
+				Content:     TextFromHTML(entry.Content),
+			}
+			for _, link := range entry.Links {
+				if link.Rel == "" || link.Rel == "alternate" {
+					item.Link = strings.TrimSpace(link.Href)
+					break
+				}
+			}
+			items = append(items, item)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported feed format <%s>", root.XMLName.Local)
+	}
+
+	// Items without a link cannot be credited, so they are dropped
+	kept := items[:0]
+	for _, item := range items {
+		if item.Link == "" || item.Title == "" {
+			continue
+		}
+		if item.GUID == "" {
+			item.GUID = item.Link
+		}
+		kept = append(kept, item)
+	}
+
+	return kept, nil
+}
+
+// get downloads a http(s) URL, reading at most maxFeedBytes
+func (c *Client) get(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	if err := checkScheme(req.URL); err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %v", url, err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: status %d", url, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", url, err)
+	}
+	return body, nil
+}
+
+// parseFeedTime parses the first of the given feed dates that is in a known format, or returns the zero time
+func parseFeedTime(values ...string) time.Time {
+	layouts := []string{time.RFC1123Z, time.RFC1123, time.RFC3339, "Mon, 2 Jan 2006 15:04:05 -0700", "Mon, 2 Jan 2006 15:04:05 MST", "2006-01-02T15:04:05Z07:00", "2006-01-02"}
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		for _, layout := range layouts {
+			if t, err := time.Parse(layout, value); err == nil {
+				return t
+			}
+		}
+	}
+	return time.Time{}
+}
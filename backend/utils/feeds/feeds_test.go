@@ -0,0 +1,334 @@
+package feeds
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const rss2Feed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:content="http://purl.org/rss/1.0/modules/content/" xmlns:dc="http://purl.org/dc/elements/1.1/">
+  <channel>
+    <title>Example Times</title>
+    <item>
+      <title>City opens new library</title>
+      <link>https://example.com/library</link>
+      <guid>library-1</guid>
+      <pubDate>Mon, 02 Jan 2006 15:04:05 -0700</pubDate>
+      <description>&lt;p&gt;A short &lt;b&gt;teaser&lt;/b&gt;.&lt;/p&gt;</description>
+      <content:encoded><![CDATA[<p>The city opened a library.</p><p>It has <em>many</em> books.</p>]]></content:encoded>
+    </item>
+    <item>
+      <title>Dated with Dublin Core</title>
+      <link>https://example.com/dc</link>
+      <dc:date>2006-01-02T15:04:05Z</dc:date>
+    </item>
+    <item>
+      <title>No link, cannot be credited</title>
+      <description>Dropped</description>
+    </item>
+    <item>
+      <title>No date at all</title>
+      <link>https://example.com/undated</link>
+    </item>
+  </channel>
+</rss>`
+
+const rss1Feed = `<?xml version="1.0"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/" xmlns:dc="http://purl.org/dc/elements/1.1/">
+  <channel rdf:about="https://example.org/">
+    <title>Example Weekly</title>
+  </channel>
+  <item rdf:about="https://example.org/science">
+    <title>Scientists find water</title>
+    <link>https://example.org/science</link>
+    <description>Water was found.</description>
+    <dc:date>2006-01-02</dc:date>
+  </item>
+  <item rdf:about="https://example.org/untitled">
+    <link>https://example.org/untitled</link>
+  </item>
+</rdf:RDF>`
+
+const atomFeed = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Example Daily</title>
+  <entry>
+    <title>Trains run on time</title>
+    <id>urn:example:trains</id>
+    <link rel="enclosure" href="https://example.net/trains.mp3"/>
+    <link rel="alternate" href="https://example.net/trains"/>
+    <published>2006-01-02T15:04:05Z</published>
+    <updated>2006-01-03T15:04:05Z</updated>
+    <summary>Trains were on time.</summary>
+    <content type="html">&lt;p&gt;Every train arrived on time.&lt;/p&gt;</content>
+  </entry>
+  <entry>
+    <title>Only updated</title>
+    <link href="https://example.net/updated"/>
+    <updated>2006-01-03T15:04:05Z</updated>
+  </entry>
+  <entry>
+    <title>Only an enclosure</title>
+    <id>urn:example:enclosure</id>
+    <link rel="enclosure" href="https://example.net/audio.mp3"/>
+  </entry>
+</feed>`
+
+const articlePage = `<!DOCTYPE html>
+<html>
+<head><title>Teaser</title><script>var tracking = "This script text is long enough to be a paragraph if it were kept.";</script></head>
+<body>
+  <nav><p>Home, World, Science, Sports, Culture, Opinion and more sections here</p></nav>
+  <p>Subscribe to our newsletter to get the best stories every single morning.</p>
+  <article>
+    <h1>Teaser story</h1>
+    <p>The first paragraph of the story is long enough to be kept by the extractor.</p>
+    <figure><p>A photo caption that is also long enough but sits in a figure.</p></figure>
+    <p>Short byline.</p>
+    <p>The second paragraph of the story <a href="/more">links</a> to more reading on the topic.</p>
+  </article>
+  <footer><p>Copyright Example Teaser Company, all rights reserved, since 1999.</p></footer>
+</body>
+</html>`
+
+// newFeedServer serves the test feeds and article page on a local address
+func newFeedServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	serve := func(path, contentType, body string) {
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", contentType)
+			w.Write([]byte(body))
+		})
+	}
+	serve("/rss.xml", "application/rss+xml", rss2Feed)
+	serve("/rdf.xml", "application/rdf+xml", rss1Feed)
+	serve("/atom.xml", "application/atom+xml", atomFeed)
+	serve("/article.html", "text/html", articlePage)
+	mux.HandleFunc("/teaser.xml", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write([]byte(`<rss version="2.0"><channel><title>Teasers</title><item>
+			<title>Teaser story</title>
+			<link>http://` + r.Host + `/article.html</link>
+			<description>Read the full story on our site.</description>
+		</item></channel></rss>`))
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/redirect", http.StatusFound)
+	})
+	mux.HandleFunc("/to-file", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "file:///etc/passwd", http.StatusFound)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// localClient is allowed to reach the test server on 127.0.0.1
+func localClient() *Client {
+	return NewClientWithConfig(Config{AllowPrivateAddresses: true})
+}
+
+func TestFetchRSS2(t *testing.T) {
+	server := newFeedServer(t)
+
+	items, err := localClient().Fetch(context.Background(), server.URL+"/rss.xml")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+
+	if len(items) != 3 {
+		t.Fatalf("got %d items, want 3 (the link-less item dropped): %+v", len(items), items)
+	}
+
+	first := items[0]
+	if first.FeedTitle != "Example Times" || first.Title != "City opens new library" || first.Link != "https://example.com/library" {
+		t.Errorf("unexpected first item: %+v", first)
+	}
+	if first.GUID != "library-1" {
+		t.Errorf("GUID = %q, want the feed's guid", first.GUID)
+	}
+	if want := time.Date(2006, 1, 2, 22, 4, 5, 0, time.UTC); !first.PublishedAt.Equal(want) {
+		t.Errorf("PublishedAt = %v, want %v", first.PublishedAt, want)
+	}
+	if first.Summary != "A short teaser." {
+		t.Errorf("Summary = %q, want HTML reduced to text", first.Summary)
+	}
+	if first.Content != "The city opened a library.\n\nIt has many books." {
+		t.Errorf("Content = %q, want content:encoded paragraphs as text", first.Content)
+	}
+
+	// dc:date is used when there is no pubDate
+	if want := time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC); !items[1].PublishedAt.Equal(want) {
+		t.Errorf("dc:date item PublishedAt = %v, want %v", items[1].PublishedAt, want)
+	}
+	// Without a guid the link identifies the item
+	if items[1].GUID != "https://example.com/dc" {
+		t.Errorf("GUID = %q, want the link as fallback", items[1].GUID)
+	}
+
+	if !items[2].PublishedAt.IsZero() {
+		t.Errorf("undated item PublishedAt = %v, want zero time", items[2].PublishedAt)
+	}
+}
+
+func TestFetchRSS1(t *testing.T) {
+	server := newFeedServer(t)
+
+	items, err := localClient().Fetch(context.Background(), server.URL+"/rdf.xml")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+
+	if len(items) != 1 {
+		t.Fatalf("got %d items, want 1 (the untitled item dropped): %+v", len(items), items)
+	}
+	item := items[0]
+	if item.FeedTitle != "Example Weekly" || item.Title != "Scientists find water" || item.Link != "https://example.org/science" {
+		t.Errorf("unexpected item: %+v", item)
+	}
+	if item.Summary != "Water was found." {
+		t.Errorf("Summary = %q", item.Summary)
+	}
+	if want := time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC); !item.PublishedAt.Equal(want) {
+		t.Errorf("PublishedAt = %v, want date-only dc:date %v", item.PublishedAt, want)
+	}
+}
+
+func TestFetchAtom(t *testing.T) {
+	server := newFeedServer(t)
+
+	items, err := localClient().Fetch(context.Background(), server.URL+"/atom.xml")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+
+	if len(items) != 2 {
+		t.Fatalf("got %d items, want 2 (the entry with only an enclosure dropped): %+v", len(items), items)
+	}
+
+	first := items[0]
+	if first.FeedTitle != "Example Daily" || first.Link != "https://example.net/trains" {
+		t.Errorf("unexpected first entry, want the alternate link rather than the enclosure: %+v", first)
+	}
+	if first.GUID != "urn:example:trains" {
+		t.Errorf("GUID = %q, want the entry id", first.GUID)
+	}
+	if want := time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC); !first.PublishedAt.Equal(want) {
+		t.Errorf("PublishedAt = %v, want published %v", first.PublishedAt, want)
+	}
+	if first.Content != "Every train arrived on time." {
+		t.Errorf("Content = %q", first.Content)
+	}
+
+	second := items[1]
+	if second.Link != "https://example.net/updated" || second.GUID != "https://example.net/updated" {
+		t.Errorf("unexpected second entry, want the rel-less link and it as GUID: %+v", second)
+	}
+	if want := time.Date(2006, 1, 3, 15, 4, 5, 0, time.UTC); !second.PublishedAt.Equal(want) {
+		t.Errorf("PublishedAt = %v, want updated %v as fallback", second.PublishedAt, want)
+	}
+}
+
+func TestParseRejectsUnknownFormat(t *testing.T) {
+	if _, err := Parse([]byte(`<html><body>Not a feed</body></html>`)); err == nil {
+		t.Error("Parse accepted an HTML page")
+	}
+}
+
+func TestExtractTeaserArticle(t *testing.T) {
+	server := newFeedServer(t)
+	client := localClient()
+
+	items, err := client.Fetch(context.Background(), server.URL+"/teaser.xml")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if len(items) != 1 || items[0].Content != "" {
+		t.Fatalf("want one teaser item without content, got %+v", items)
+	}
+
+	text, err := client.ExtractArticle(context.Background(), items[0].Link)
+	if err != nil {
+		t.Fatalf("ExtractArticle: %v", err)
+	}
+
+	want := "The first paragraph of the story is long enough to be kept by the extractor.\n\n" +
+		"The second paragraph of the story links to more reading on the topic."
+	if text != want {
+		t.Errorf("ExtractArticle = %q, want %q", text, want)
+	}
+}
+
+func TestExtractArticleTextWithoutArticleElement(t *testing.T) {
+	page := `<html><body>
+		<header><p>Site header text that is long enough to count as a paragraph.</p></header>
+		<div><p>First body paragraph that is long enough to count as article text.</p></div>
+		<p>Too short.</p>
+		<aside><p>Related stories sidebar text that is long enough to be a paragraph.</p></aside>
+		<div><p>Second body paragraph that is long enough to count as article text.</p></div>
+	</body></html>`
+
+	text, err := ExtractArticleText([]byte(page))
+	if err != nil {
+		t.Fatalf("ExtractArticleText: %v", err)
+	}
+
+	want := "First body paragraph that is long enough to count as article text.\n\n" +
+		"Second body paragraph that is long enough to count as article text."
+	if text != want {
+		t.Errorf("ExtractArticleText = %q, want %q", text, want)
+	}
+}
+
+func TestExtractArticleTextPrefersMain(t *testing.T) {
+	page := `<html><body>
+		<p>Promotional paragraph outside the main content, long enough to count.</p>
+		<main><p>Main content paragraph that is long enough to count as the article.</p></main>
+	</body></html>`
+
+	text, err := ExtractArticleText([]byte(page))
+	if err != nil {
+		t.Fatalf("ExtractArticleText: %v", err)
+	}
+	if text != "Main content paragraph that is long enough to count as the article." {
+		t.Errorf("ExtractArticleText = %q, want only the <main> paragraph", text)
+	}
+}
+
+func TestClientRefusesPrivateAddresses(t *testing.T) {
+	server := newFeedServer(t)
+
+	_, err := NewClient().Fetch(context.Background(), server.URL+"/rss.xml")
+	if !errors.Is(err, errPrivateAddress) {
+		t.Errorf("Fetch from %s = %v, want errPrivateAddress", server.URL, err)
+	}
+}
+
+func TestClientRefusesOtherSchemes(t *testing.T) {
+	server := newFeedServer(t)
+	client := localClient()
+
+	if _, err := client.ExtractArticle(context.Background(), "file:///etc/passwd"); err == nil || !strings.Contains(err.Error(), "unsupported URL scheme") {
+		t.Errorf("ExtractArticle(file URL) = %v, want an unsupported scheme error", err)
+	}
+	if _, err := client.ExtractArticle(context.Background(), server.URL+"/to-file"); err == nil || !strings.Contains(err.Error(), "unsupported URL scheme") {
+		t.Errorf("ExtractArticle(redirect to file URL) = %v, want an unsupported scheme error", err)
+	}
+}
+
+func TestClientLimitsRedirects(t *testing.T) {
+	server := newFeedServer(t)
+
+	_, err := localClient().ExtractArticle(context.Background(), server.URL+"/redirect")
+	if err == nil || !strings.Contains(err.Error(), "redirects") {
+		t.Errorf("ExtractArticle(redirect loop) = %v, want a redirect limit error", err)
+	}
+}
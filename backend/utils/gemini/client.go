@@ -0,0 +1,100 @@
+package gemini
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+const (
+	// flashModel is the default model for short structured tasks like grading and glossing
+	flashModel = "gemini-2.0-flash"
+)
+
+// generateContent sends a single prompt to the given model and returns the raw text of the first candidate
+func generateContent(ctx context.Context, model, prompt string, tools []Tool) (string, error) {
+	apiKey := os.Getenv("GEMINI_KEY")
+	if apiKey == "" {
+		return "", fmt.Errorf("GEMINI_KEY environment variable is not set")
+	}
+
+	reqBody := GeminiRequestWithTools{
+		Contents: []Content{
+			{
+				Parts: []Part{
+					{Text: prompt},
+				},
+			},
+		},
+		Tools: tools,
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	url := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/%s:generateContent?key=%s", model, apiKey)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %v", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("failed to call Gemini API: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("gemini API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var geminiResp GeminiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&geminiResp); err != nil {
+		return "", fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	if len(geminiResp.Candidates) == 0 || len(geminiResp.Candidates[0].Content.Parts) == 0 {
+		return "", fmt.Errorf("no response from Gemini API")
+	}
+
+	return geminiResp.Candidates[0].Content.Parts[0].Text, nil
+}
+
+// generateJSON sends a prompt that asks for a JSON object and decodes the answer into out
+func generateJSON(ctx context.Context, model, prompt string, out interface{}) error {
+	responseText, err := generateContent(ctx, model, prompt, nil)
+	if err != nil {
+		return err
+	}
+	return parseJSONResponse(responseText, out)
+}
+
+// parseJSONResponse extracts the JSON object from a Gemini answer, tolerating markdown fences and surrounding prose
+func parseJSONResponse(responseText string, out interface{}) error {
+	responseText = strings.TrimSpace(responseText)
+	responseText = strings.TrimPrefix(responseText, "```json")
+	responseText = strings.TrimSuffix(responseText, "```")
+	responseText = strings.TrimSpace(responseText)
+
+	startIdx := strings.Index(responseText, "{")
+	endIdx := strings.LastIndex(responseText, "}")
+	if startIdx == -1 || endIdx == -1 || startIdx >= endIdx {
+		return fmt.Errorf("failed to find valid JSON in Gemini response. Full response: %s", responseText)
+	}
+
+	jsonStr := cleanJSONString(responseText[startIdx : endIdx+1])
+	if err := json.Unmarshal([]byte(jsonStr), out); err != nil {
+		return fmt.Errorf("failed to parse Gemini response as JSON: %v. Extracted JSON: %s", err, jsonStr)
+	}
+
+	return nil
+}
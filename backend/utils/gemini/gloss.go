@@ -0,0 +1,61 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+type GlossInput struct {
+	Index     int      `json:"index"`
+	Text      string   `json:"text"`
+	HardWords []string `json:"hard_words"`
+}
+
+type WordGloss struct {
+	Word  string `json:"word"`
+	Gloss string `json:"gloss"`
+}
+
+type SentenceGloss struct {
+	Index       int         `json:"index"`
+	Translation string      `json:"translation"`
+	Glosses     []WordGloss `json:"glosses"`
+}
+
+// GlossSentences uses Gemini to translate sentences into traditional Chinese and gloss their hard words in context
+func GlossSentences(ctx context.Context, sentences []GlossInput) ([]SentenceGloss, error) {
+	sentencesJSON, err := json.Marshal(sentences)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal sentences: %v", err)
+	}
+
+	prompt := fmt.Sprintf(`You are helping an English language learner whose native language is Chinese read an English article.
+
+Below are consecutive sentences from the article as JSON. Each has an "index", the sentence "text" and "hard_words" the learner probably does not know.
+
+SENTENCES:
+%s
+
+INSTRUCTIONS:
+1. Translate every sentence into natural traditional Chinese, keeping its meaning in the context of the surrounding sentences
+2. For each hard word, write a very short gloss in traditional Chinese (2-8 characters) for the meaning it has IN THAT SENTENCE
+3. Copy "word" exactly as it appears in "hard_words"
+4. Skip hard words that are names of people, places or organizations
+
+Respond in this exact JSON format, with one entry per sentence using the same "index":
+{
+  "sentences": [
+    {"index": 0, "translation": "...", "glosses": [{"word": "...", "gloss": "..."}]}
+  ]
+}`, sentencesJSON)
+
+	var result struct {
+		Sentences []SentenceGloss `json:"sentences"`
+	}
+	if err := generateJSON(ctx, flashModel, prompt, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Sentences, nil
+}
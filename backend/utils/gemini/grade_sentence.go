@@ -0,0 +1,60 @@
+package gemini
+
+import (
+	"context"
+	"fmt"
+)
+
+type SentenceGradeResult struct {
+	IsCorrect         bool   `json:"is_correct"`         // Grammatical and uses the target word correctly
+	UsesTargetWord    bool   `json:"uses_target_word"`   // The target word (or an inflection of it) appears in the sentence
+	Correction        string `json:"correction"`         // Minimal grammatical fix of the learner's sentence
+	NaturalnessScore  int    `json:"naturalness_score"`  // 1-10, how natural the sentence sounds to a native speaker
+	BetterAlternative string `json:"better_alternative"` // A more natural way to say the same thing
+	Feedback          string `json:"feedback"`           // Short explanation in traditional Chinese
+}
+
+// GradeSentence uses Gemini to grade a learner's own sentence using the target word
+func GradeSentence(ctx context.Context, word, sentence string) (*SentenceGradeResult, error) {
+	prompt := fmt.Sprintf(`You are an English teacher grading a sentence written by an English language learner whose native language is Chinese.
+
+The learner was asked to write their own sentence using the word "%s".
+
+Learner's sentence: "%s"
+
+GRADING INSTRUCTIONS:
+1. Check whether the sentence uses "%s" (any inflected form counts, e.g. "studied" for "study")
+2. Check grammar, spelling and whether the word is used with the right meaning and part of speech
+3. "is_correct" is true only if the sentence uses the word, is grammatical AND uses the word with a correct meaning
+4. "correction" is the learner's sentence with the smallest changes needed to make it correct (same as the original if already correct)
+5. "naturalness_score" is 1-10: 1 = nobody would say this, 10 = a native speaker would say exactly this
+6. "better_alternative" is a more natural sentence with the same meaning that still uses the word
+7. "feedback" is one or two short, encouraging sentences in traditional Chinese explaining the main issue (or praising a good sentence)
+
+Respond in this exact JSON format:
+{
+  "is_correct": true/false,
+  "uses_target_word": true/false,
+  "correction": "corrected sentence",
+  "naturalness_score": 1-10,
+  "better_alternative": "more natural sentence",
+  "feedback": "short feedback in traditional Chinese"
+}`, word, sentence, word)
+
+	var result SentenceGradeResult
+	if err := generateJSON(ctx, flashModel, prompt, &result); err != nil {
+		return nil, err
+	}
+
+	// Clamp the score to the documented range
+	if result.NaturalnessScore < 1 {
+		result.NaturalnessScore = 1
+	} else if result.NaturalnessScore > 10 {
+		result.NaturalnessScore = 10
+	}
+	if !result.UsesTargetWord {
+		result.IsCorrect = false
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,175 @@
+package gemini
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"google-devjam-backend/model"
+)
+
+const (
+	// groundingRedirectHost serves the redirect links Gemini reports for search results; they expire after a while
+	groundingRedirectHost = "vertexaisearch.cloud.google.com"
+	// resolveSourcesTimeout bounds resolving all of an article's redirect links
+	resolveSourcesTimeout = 15 * time.Second
+)
+
+// redirectClient reports redirects instead of following them, so the publisher URL can be read without fetching it
+var redirectClient = &http.Client{
+	Timeout: resolveSourcesTimeout,
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// GroundingMetadata is what Gemini reports about the Google Search results a grounded answer is based on
+type GroundingMetadata struct {
+	WebSearchQueries  []string           `json:"webSearchQueries,omitempty"`
+	GroundingChunks   []GroundingChunk   `json:"groundingChunks,omitempty"`
+	GroundingSupports []GroundingSupport `json:"groundingSupports,omitempty"`
+}
+
+// GroundingChunk is one retrieved search result
+type GroundingChunk struct {
+	Web *WebChunk `json:"web,omitempty"`
+}
+
+type WebChunk struct {
+	URI   string `json:"uri"`
+	Title string `json:"title"`
+}
+
+// GroundingSupport links a segment of the answer to the chunks that support it
+type GroundingSupport struct {
+	Segment               GroundingSegment `json:"segment"`
+	GroundingChunkIndices []int            `json:"groundingChunkIndices"`
+	ConfidenceScores      []float64        `json:"confidenceScores,omitempty"`
+}
+
+// GroundingSegment is a span of the answer text; indices are byte offsets into the answer part
+type GroundingSegment struct {
+	PartIndex  int    `json:"partIndex,omitempty"`
+	StartIndex int    `json:"startIndex,omitempty"`
+	EndIndex   int    `json:"endIndex"`
+	Text       string `json:"text"`
+}
+
+// groundingSources turns grounding metadata into one source per distinct web page, in the order Gemini retrieved
+// them, each with the answer segments it supports. Claims are not located in any article yet.
+func groundingSources(metadata *GroundingMetadata) []model.NewsSource {
+	if metadata == nil {
+		return nil
+	}
+
+	var sources []model.NewsSource
+	sourceIndex := make(map[string]int)                       // URI to index in sources
+	chunkSource := make([]int, len(metadata.GroundingChunks)) // Chunk index to index in sources, -1 for non-web chunks
+	for i, chunk := range metadata.GroundingChunks {
+		chunkSource[i] = -1
+		if chunk.Web == nil || chunk.Web.URI == "" {
+			continue
+		}
+		index, ok := sourceIndex[chunk.Web.URI]
+		if !ok {
+			index = len(sources)
+			sourceIndex[chunk.Web.URI] = index
+			sources = append(sources, model.NewsSource{URL: chunk.Web.URI, Title: chunk.Web.Title})
+		}
+		chunkSource[i] = index
+	}
+
+	for _, support := range metadata.GroundingSupports {
+		text := claimText(support.Segment.Text)
+		if text == "" {
+			continue
+		}
+		for j, chunkIndex := range support.GroundingChunkIndices {
+			if chunkIndex < 0 || chunkIndex >= len(chunkSource) || chunkSource[chunkIndex] < 0 {
+				continue
+			}
+			claim := model.NewsClaim{Text: text}
+			if j < len(support.ConfidenceScores) {
+				claim.Confidence = support.ConfidenceScores[j]
+			}
+			source := &sources[chunkSource[chunkIndex]]
+			source.Claims = append(source.Claims, claim)
+		}
+	}
+
+	return sources
+}
+
+// resolveSourceURLs replaces Gemini's expiring grounding redirect links with the publisher URLs they point to,
+// so sources stay checkable after the article is generated. A link that cannot be resolved is kept as it is.
+func resolveSourceURLs(sources []model.NewsSource) {
+	ctx, cancel := context.WithTimeout(context.Background(), resolveSourcesTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for i := range sources {
+		parsed, err := url.Parse(sources[i].URL)
+		if err != nil || parsed.Host != groundingRedirectHost {
+			continue
+		}
+
+		wg.Add(1)
+		go func(source *model.NewsSource) {
+			defer wg.Done()
+			if resolved, ok := resolveRedirect(ctx, source.URL); ok {
+				source.URL = resolved
+			}
+		}(&sources[i])
+	}
+	wg.Wait()
+}
+
+// resolveRedirect returns where a redirect link points, if it answers with a redirect to an http(s) URL
+func resolveRedirect(ctx context.Context, link string) (string, bool) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return "", false
+	}
+	resp, err := redirectClient.Do(req)
+	if err != nil {
+		return "", false
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 300 || resp.StatusCode >= 400 {
+		return "", false
+	}
+	location, err := resp.Location()
+	if err != nil || (location.Scheme != "http" && location.Scheme != "https") {
+		return "", false
+	}
+	return location.String(), true
+}
+
+// sourceTitles returns the distinct titles of sources in order. Titles are usually the publisher's domain,
+// so several pages from one outlet share a title.
+func sourceTitles(sources []model.NewsSource) []string {
+	var titles []string
+	seen := make(map[string]bool)
+	for _, source := range sources {
+		if source.Title == "" || seen[source.Title] {
+			continue
+		}
+		seen[source.Title] = true
+		titles = append(titles, source.Title)
+	}
+	return titles
+}
+
+// claimText cleans a grounded segment. Article answers are JSON, so segments taken from inside the content string
+// still carry its escapes and may include the surrounding quotes or field names.
+func claimText(segment string) string {
+	segment = strings.NewReplacer(`\"`, `"`, `\n`, " ", `\t`, " ", `\\`, `\`).Replace(segment)
+	if i := strings.Index(segment, `"content": "`); i >= 0 {
+		segment = segment[i+len(`"content": "`):]
+	}
+	return strings.Trim(strings.TrimSpace(segment), `",`)
+}
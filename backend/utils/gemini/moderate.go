@@ -0,0 +1,61 @@
+package gemini
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// SafetyClassification is Gemini's judgement of whether a generated article is fit to show a learner
+type SafetyClassification struct {
+	Safe       bool     `json:"safe"`
+	Categories []string `json:"categories"` // Problem categories, empty when safe
+	Reason     string   `json:"reason"`     // One sentence explaining the decision
+}
+
+// ClassifyContentSafety asks Gemini whether a generated news article is safe for a general adult audience,
+// or for children when kidSafe is set
+func ClassifyContentSafety(ctx context.Context, title, content string, kidSafe bool) (*SafetyClassification, error) {
+	audience := "adult English language learners reading a friendly news podcast"
+	if kidSafe {
+		audience = "children aged 8-12 learning English; anything a parent would not want a child to read is unsafe"
+	}
+
+	prompt := fmt.Sprintf(`You are a content safety reviewer for an English learning app that generates news articles.
+
+AUDIENCE: %s
+
+ARTICLE:
+Title: %s
+
+%s
+
+REVIEW INSTRUCTIONS:
+1. Decide whether the article is safe to show this audience
+2. Reporting real news on serious topics is fine when it is factual and not graphic
+3. Mark the article unsafe for: sexual content, hate or harassment, graphic violence or gore, self-harm encouragement, profanity, instructions for dangerous or illegal activities, or personal contact details
+4. For children also mark unsafe: violent crime, war or terrorism in detail, drugs, alcohol, gambling and frightening content
+5. "categories" lists short snake_case names for each problem found (e.g. "graphic_violence", "sexual"); leave it empty when safe
+6. "reason" is one short English sentence explaining the decision
+
+Respond in this exact JSON format:
+{
+  "safe": true/false,
+  "categories": ["category"],
+  "reason": "short explanation"
+}`, audience, title, content)
+
+	var result SafetyClassification
+	if err := generateJSON(ctx, flashModel, prompt, &result); err != nil {
+		return nil, err
+	}
+
+	for i, category := range result.Categories {
+		result.Categories[i] = strings.ToLower(strings.TrimSpace(category))
+	}
+	if !result.Safe && len(result.Categories) == 0 {
+		result.Categories = []string{"unsafe"}
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,178 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	QuestionMultipleChoice = "multiple_choice"
+	QuestionShortAnswer    = "short_answer"
+	QuestionVocabulary     = "vocabulary" // Multiple choice on the meaning of a word as used in the article
+)
+
+const (
+	// minComprehensionQuestions and maxComprehensionQuestions bound the multiple choice and short answer questions
+	minComprehensionQuestions = 3
+	maxComprehensionQuestions = 5
+	// maxVocabularyQuestions bounds the vocabulary-in-context questions
+	maxVocabularyQuestions = 3
+)
+
+type GeneratedQuestion struct {
+	Type        string   `json:"type"`
+	Question    string   `json:"question"`
+	Options     []string `json:"options,omitempty"`
+	Answer      string   `json:"answer"` // The correct option, or a reference answer for short answers
+	Explanation string   `json:"explanation"`
+	Word        string   `json:"word,omitempty"` // Target word of a vocabulary question
+}
+
+type NewsQuestionsResult struct {
+	Summary   string              `json:"summary"`
+	Questions []GeneratedQuestion `json:"questions"`
+}
+
+type ShortAnswerItem struct {
+	ID              string `json:"id"`
+	Question        string `json:"question"`
+	ReferenceAnswer string `json:"reference_answer"`
+	Answer          string `json:"answer"`
+}
+
+type ShortAnswerGrade struct {
+	ID       string `json:"id"`
+	Correct  bool   `json:"correct"`
+	Feedback string `json:"feedback"`
+}
+
+// GenerateNewsQuestions uses Gemini to write a one-line summary and comprehension questions for an article.
+// Extra questions are dropped; fewer than 3 usable comprehension questions is an error.
+func GenerateNewsQuestions(ctx context.Context, title, content string, words []string) (*NewsQuestionsResult, error) {
+	wordsStr := "none"
+	if len(words) > 0 {
+		wordsStr = strings.Join(words, ", ")
+	}
+
+	prompt := fmt.Sprintf(`You are an English teacher writing reading comprehension practice for an English language learner whose native language is Chinese.
+
+ARTICLE TITLE: %s
+
+ARTICLE:
+%s
+
+VOCABULARY THE LEARNER IS STUDYING: %s
+
+INSTRUCTIONS:
+1. Write a one-line summary of the article in simple English
+2. Write 3-5 comprehension questions about the article:
+   - Mix "multiple_choice" questions (exactly 4 options) and "short_answer" questions
+   - Questions must be answerable from the article alone
+3. For up to 3 of the studied vocabulary words that appear in the article, write a "vocabulary" question asking what the word means as used in the article, with exactly 4 options
+4. For multiple choice and vocabulary questions, "answer" must be copied exactly from "options"
+5. For short answer questions, "answer" is a short reference answer
+6. "explanation" is one short sentence in traditional Chinese explaining the answer
+
+Respond in this exact JSON format:
+{
+  "summary": "one-line summary",
+  "questions": [
+    {"type": "multiple_choice", "question": "...", "options": ["...", "...", "...", "..."], "answer": "...", "explanation": "..."},
+    {"type": "short_answer", "question": "...", "answer": "...", "explanation": "..."},
+    {"type": "vocabulary", "word": "...", "question": "...", "options": ["...", "...", "...", "..."], "answer": "...", "explanation": "..."}
+  ]
+}`, title, content, wordsStr)
+
+	var result NewsQuestionsResult
+	if err := generateJSON(ctx, flashModel, prompt, &result); err != nil {
+		return nil, err
+	}
+
+	// Drop malformed and surplus questions rather than storing questions that cannot be graded
+	questions := make([]GeneratedQuestion, 0, len(result.Questions))
+	comprehension, vocabulary := 0, 0
+	for _, q := range result.Questions {
+		if strings.TrimSpace(q.Question) == "" || strings.TrimSpace(q.Answer) == "" {
+			continue
+		}
+		switch q.Type {
+		case QuestionMultipleChoice, QuestionVocabulary:
+			if len(q.Options) < 2 || !containsOption(q.Options, q.Answer) {
+				continue
+			}
+		case QuestionShortAnswer:
+			q.Options = nil
+		default:
+			continue
+		}
+
+		if q.Type == QuestionVocabulary {
+			if vocabulary == maxVocabularyQuestions {
+				continue
+			}
+			vocabulary++
+		} else {
+			if comprehension == maxComprehensionQuestions {
+				continue
+			}
+			comprehension++
+		}
+		questions = append(questions, q)
+	}
+	result.Questions = questions
+
+	if comprehension < minComprehensionQuestions {
+		return nil, fmt.Errorf("only %d usable comprehension questions in Gemini response, need at least %d", comprehension, minComprehensionQuestions)
+	}
+
+	return &result, nil
+}
+
+// GradeShortAnswers uses Gemini to grade a learner's short answers about an article in one call
+func GradeShortAnswers(ctx context.Context, content string, items []ShortAnswerItem) ([]ShortAnswerGrade, error) {
+	itemsJSON, err := json.Marshal(items)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal answers: %v", err)
+	}
+
+	prompt := fmt.Sprintf(`You are an English teacher grading short answers to reading comprehension questions. The learner's native language is Chinese.
+
+ARTICLE:
+%s
+
+ANSWERS TO GRADE (JSON):
+%s
+
+GRADING INSTRUCTIONS:
+1. An answer is correct if it shows the learner understood the article, even if the wording differs from the reference answer
+2. Do not mark answers wrong for small grammar or spelling mistakes
+3. "feedback" is one short, encouraging sentence in traditional Chinese
+
+Respond in this exact JSON format, with one grade per answer using the same "id":
+{
+  "grades": [
+    {"id": "...", "correct": true/false, "feedback": "..."}
+  ]
+}`, content, itemsJSON)
+
+	var result struct {
+		Grades []ShortAnswerGrade `json:"grades"`
+	}
+	if err := generateJSON(ctx, flashModel, prompt, &result); err != nil {
+		return nil, err
+	}
+
+	return result.Grades, nil
+}
+
+// containsOption reports whether answer is one of the options, ignoring case and surrounding space
+func containsOption(options []string, answer string) bool {
+	for _, option := range options {
+		if strings.EqualFold(strings.TrimSpace(option), strings.TrimSpace(answer)) {
+			return true
+		}
+	}
+	return false
+}
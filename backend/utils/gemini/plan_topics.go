@@ -0,0 +1,75 @@
+package gemini
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+type newsTopicsResult struct {
+	Topics []string `json:"topics"`
+}
+
+// PlanNewsTopics picks distinct current stories for a batch of articles in a single call,
+// so the articles can then be written concurrently without covering the same subject
+func PlanNewsTopics(ctx context.Context, req NewsGenerationRequest, count int) ([]string, error) {
+	interests := []string{"general news"}
+	if req.UserPreferences != nil && len(req.UserPreferences.Interests) > 0 {
+		interests = req.UserPreferences.Interests
+	}
+
+	existingTitlesStr := "none"
+	if len(req.ExistingTitles) > 0 {
+		existingTitlesStr = "- " + strings.Join(req.ExistingTitles, "\n- ")
+	}
+
+	prompt := fmt.Sprintf(`You are planning a batch of short news articles for an English language learner.
+
+Use the Google Search tool to find current, real news related to these interests: %s
+
+Pick exactly %d news stories for the batch.
+
+RULES:
+1. Every story must be about a COMPLETELY DIFFERENT subject and theme from the others (e.g. one on technology, one on sports, one on health)
+2. Do not pick stories that cover the same subject as these previously generated titles:
+%s
+3. Prefer recent stories with enough facts for an 800-1200 word article
+4. Describe each story in one specific sentence (who, what, where), not a vague category
+
+Respond in this exact JSON format:
+{
+  "topics": ["one-sentence description of story 1", "one-sentence description of story 2"]
+}`, strings.Join(interests, ", "), count, existingTitlesStr)
+
+	responseText, err := generateContent(ctx, flashModel, prompt, []Tool{{GoogleSearch: &GoogleSearchTool{}}})
+	if err != nil {
+		return nil, err
+	}
+
+	var result newsTopicsResult
+	if err := parseJSONResponse(responseText, &result); err != nil {
+		return nil, err
+	}
+
+	// Drop blanks and repeats the model may return
+	seen := make(map[string]bool)
+	topics := make([]string, 0, count)
+	for _, topic := range result.Topics {
+		topic = strings.TrimSpace(topic)
+		key := strings.ToLower(topic)
+		if topic == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		topics = append(topics, topic)
+		if len(topics) == count {
+			break
+		}
+	}
+
+	if len(topics) == 0 {
+		return nil, fmt.Errorf("no topics in Gemini response")
+	}
+
+	return topics, nil
+}
@@ -0,0 +1,43 @@
+package gemini
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ReviseNewsWithWords uses Gemini to work missing vocabulary words into an article with as few changes as possible
+func ReviseNewsWithWords(ctx context.Context, title, content string, missingWords []string) (string, error) {
+	prompt := fmt.Sprintf(`You are editing a casual, podcast-style news article written for English language learners.
+
+ARTICLE TITLE: %s
+
+ARTICLE:
+%s
+
+The article was supposed to use these vocabulary words but does not: %s
+
+EDITING INSTRUCTIONS:
+1. Revise the article so that EVERY listed word appears at least once (inflected forms like "studied" for "study" are fine)
+2. Use each word naturally and with its common meaning, in a sentence that fits the surrounding text
+3. Change as little as possible: keep the topic, facts, tone, length, paragraphs and difficulty the same
+4. Do not add headings, notes, lists of words or any commentary about the edit
+
+Respond in this exact JSON format:
+{
+  "content": "the full revised article"
+}`, title, content, strings.Join(missingWords, ", "))
+
+	var result struct {
+		Content string `json:"content"`
+	}
+	if err := generateJSON(ctx, flashModel, prompt, &result); err != nil {
+		return "", err
+	}
+
+	if strings.TrimSpace(result.Content) == "" {
+		return "", fmt.Errorf("invalid response: missing content")
+	}
+
+	return result.Content, nil
+}
@@ -0,0 +1,85 @@
+package gemini
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// maxOriginalRunes bounds how much of a source article is sent to Gemini
+const maxOriginalRunes = 12000
+
+// OriginalArticle is a real article to rewrite for a learner
+type OriginalArticle struct {
+	Title     string
+	Text      string
+	Publisher string
+}
+
+// RewriteNewsArticle rewrites a real news article at the learner's level, working in their learn and review words.
+// Facts come only from the original, so the result can be credited to it; no search is done.
+// req.Topic and req.ExistingTitles are ignored, since the story is already chosen.
+func RewriteNewsArticle(ctx context.Context, req NewsGenerationRequest, original OriginalArticle) (*NewsGenerationResult, error) {
+	numericLevel, adaptiveLevel := determineAdaptiveLevel(req)
+	difficultyInstruction := generateDifficultyInstruction(numericLevel, adaptiveLevel, req)
+
+	learnWordsStr := "none"
+	if len(req.LearnWords) > 0 {
+		learnWordsStr = strings.Join(req.LearnWords, ", ")
+	}
+
+	reviewWordsStr := "none"
+	if len(req.ReviewWords) > 0 {
+		reviewWordsStr = strings.Join(req.ReviewWords, ", ")
+	}
+
+	text := original.Text
+	if runes := []rune(text); len(runes) > maxOriginalRunes {
+		text = string(runes[:maxOriginalRunes])
+	}
+
+	prompt := fmt.Sprintf(`You are a casual, friendly news presenter rewriting a real news article for English language learners.
+
+ORIGINAL ARTICLE (from %s):
+Title: %s
+
+%s
+
+LEARNER PROFILE:
+- Learning Level: %s (1-10 scale)
+- Words Currently Learning: %s
+- Words Needing Review: %s
+
+ADAPTIVE DIFFICULTY INSTRUCTION:
+%s
+%s
+REWRITING INSTRUCTIONS:
+1. Retell the same story in a relaxed, conversational, podcast-like tone (500-900 words)
+2. Use ONLY facts from the original article: do not add names, numbers, quotes, dates or events it does not contain
+3. Keep every number, name and quote you use exactly as the original states it
+4. Use vocabulary and sentence structures suited to learning level %s
+5. Naturally include as many of the learning and review words as fit the story, with their common meanings
+6. Mention near the start that the story was reported by %s
+7. Do not copy whole sentences from the original; explain the story in your own words
+
+Respond in this exact JSON format:
+{
+  "title": "Catchy, conversational title about this story",
+  "content": "The full rewritten article",
+  "level": "%s",
+  "keywords": ["key", "topic", "words", "from", "article"]
+}
+
+Keywords should be 8-12 important topic words from the article.`, original.Publisher, original.Title, text, numericLevel, learnWordsStr, reviewWordsStr, difficultyInstruction, audienceInstruction(req), numericLevel, original.Publisher, numericLevel)
+
+	var result NewsGenerationResult
+	if err := generateJSON(ctx, flashModel, prompt, &result); err != nil {
+		return nil, err
+	}
+
+	if result.Title == "" || result.Content == "" {
+		return nil, fmt.Errorf("invalid response: missing title or content")
+	}
+
+	return &result, nil
+}
@@ -17,6 +17,7 @@ type NewsGenerationRequest struct {
 	LearnWords      []string               `json:"learn_words"`     // Words user is currently learning
 	ReviewWords     []string               `json:"review_words"`    // Words that need review based on forgetting curve
 	ExistingTitles  []string               `json:"existing_titles"` // Previously generated titles to avoid duplicate topics
+	Topic           string                 `json:"topic,omitempty"` // Story planned in advance with PlanNewsTopics; empty lets Gemini choose
 }
 
 type NewsGenerationResult struct {
@@ -25,6 +26,10 @@ type NewsGenerationResult struct {
 	Level    string   `json:"level"`
 	Keywords []string `json:"keywords"`
 	Source   []string `json:"source"`
+
+	// Filled from the response's grounding metadata, not the model's JSON
+	Sources       []model.NewsSource `json:"-"` // Web pages the article is based on; claims are not located yet
+	SearchQueries []string           `json:"-"` // What Gemini searched for
 }
 
 // Tool represents a tool that can be used by Gemini
@@ -81,6 +86,16 @@ func GeneratePersonalizedNews(req NewsGenerationRequest) (*NewsGenerationResult,
 		existingTitlesStr = "- " + existingTitlesStr
 	}
 
+	// Pin the story when topics were planned for the whole batch up front
+	topicInstruction := ""
+	if req.Topic != "" {
+		topicInstruction = fmt.Sprintf(`
+ASSIGNED TOPIC (REQUIRED):
+Write about this story: %s
+Search for current facts about this specific story. Other articles in this batch cover different topics, so do not drift into another subject.
+`, req.Topic)
+	}
+
 	// Construct the prompt
 	prompt := fmt.Sprintf(`You are a casual, friendly news presenter creating content for English language learners. Write like you're having a relaxed conversation or hosting a podcast - be natural, engaging, and approachable.
 
@@ -94,7 +109,7 @@ USER PROFILE (ONLY use this information, don't assume anything else):
 
 ADAPTIVE DIFFICULTY INSTRUCTION:
 %s
-
+%s%s
 IMPORTANT: AVOID DUPLICATE TOPICS AND CONTENT
 Previously generated news titles (DO NOT cover the same topics or themes):
 %s
@@ -131,7 +146,7 @@ Respond in this exact JSON format:
   "content": "Full casual, podcast-style article (800-1200 words) that feels like a friendly conversation while naturally incorporating vocabulary words",
   "level": "%s",
   "keywords": ["key", "topic", "words", "from", "article"],
-  "source": ["Google Search", "Real-time News", "AI Generated Content"]
+  "source": ["Names of the news outlets your search results came from"]
 }
 
 IMPORTANT NOTES:
@@ -145,7 +160,7 @@ IMPORTANT NOTES:
 - Include personal reactions like "I found this pretty interesting..." or "This made me think..."
 - Use everyday expressions and contractions appropriate for the learning level
 - Make it feel like daily conversation, not a formal presentation
-- CRITICAL: Choose a completely different TOPIC/THEME from the previously generated news. Don't just change the title - change the entire subject matter and focus area`, interestsStr, numericLevel, interestsStr, learnWordsStr, reviewWordsStr, difficultyInstruction, existingTitlesStr, numericLevel, interestsStr, numericLevel)
+- CRITICAL: Choose a completely different TOPIC/THEME from the previously generated news. Don't just change the title - change the entire subject matter and focus area`, interestsStr, numericLevel, interestsStr, learnWordsStr, reviewWordsStr, difficultyInstruction, topicInstruction, audienceInstruction(req), existingTitlesStr, numericLevel, interestsStr, numericLevel)
 
 	// Create request with Google Search tool
 	reqBody := GeminiRequestWithTools{
@@ -224,6 +239,16 @@ IMPORTANT NOTES:
 		return nil, fmt.Errorf("invalid response: missing title or content")
 	}
 
+	// The sources the model writes into its JSON are unverified; prefer the pages Google Search actually returned
+	if metadata := geminiResp.Candidates[0].GroundingMetadata; metadata != nil {
+		result.Sources = groundingSources(metadata)
+		result.SearchQueries = metadata.WebSearchQueries
+		resolveSourceURLs(result.Sources)
+		if titles := sourceTitles(result.Sources); len(titles) > 0 {
+			result.Source = titles
+		}
+	}
+
 	return &result, nil
 }
 
@@ -313,6 +338,18 @@ func determineAdaptiveLevel(req NewsGenerationRequest) (string, string) {
 	return fmt.Sprintf("%d", adaptiveNumericLevel), adaptiveLevel
 }
 
+// audienceInstruction adds content rules for users in kid-safe mode; it is empty for everyone else
+func audienceInstruction(req NewsGenerationRequest) string {
+	if req.UserPreferences == nil || !req.UserPreferences.KidSafe {
+		return ""
+	}
+	return `
+KID-SAFE AUDIENCE (REQUIRED):
+The reader is a child. Choose a cheerful, age-appropriate story and avoid crime, violence, war, disasters with casualties, drugs, alcohol, gambling and romance.
+Do not include profanity, frightening details, email addresses or phone numbers.
+`
+}
+
 // generateDifficultyInstruction creates specific instructions for adaptive difficulty
 func generateDifficultyInstruction(numericLevel, adaptiveLevel string, req NewsGenerationRequest) string {
 	// Convert back to text level for comparison
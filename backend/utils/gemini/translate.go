@@ -2,6 +2,7 @@ package gemini
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -27,7 +28,8 @@ type GeminiResponse struct {
 }
 
 type Candidate struct {
-	Content Content `json:"content"`
+	Content           Content            `json:"content"`
+	GroundingMetadata *GroundingMetadata `json:"groundingMetadata,omitempty"` // Set when the Google Search tool was used
 }
 
 type TranslationResult struct {
@@ -44,6 +46,11 @@ type TranslationResult struct {
 
 // TranslateWord uses Gemini API to translate and validate a word
 func TranslateWord(word string) (*TranslationResult, error) {
+	return TranslateWordWithContext(context.Background(), word)
+}
+
+// TranslateWordWithContext is TranslateWord with a context that can cancel or time out the API call
+func TranslateWordWithContext(ctx context.Context, word string) (*TranslationResult, error) {
 	apiKey := os.Getenv("GEMINI_KEY")
 	if apiKey == "" {
 		return nil, fmt.Errorf("GEMINI_KEY environment variable is not set")
@@ -138,7 +145,13 @@ Examples:
 
 	// Make API call
 	url := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/gemini-1.5-flash:generateContent?key=%s", apiKey)
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(jsonData))
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call Gemini API: %v", err)
 	}
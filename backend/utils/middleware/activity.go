@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"google-devjam-backend/utils/mongodb"
+)
+
+// activityWriteInterval limits last_active_at writes to one per user per interval
+const activityWriteInterval = time.Hour
+
+// lastActivityWrites remembers when each user's activity was last written, keyed by user ID
+var lastActivityWrites sync.Map
+
+// touchUserActivity records that the user made an authenticated request, so background jobs can skip inactive users
+func touchUserActivity(userID string) {
+	now := time.Now()
+	if last, ok := lastActivityWrites.Load(userID); ok && now.Sub(last.(time.Time)) < activityWriteInterval {
+		return
+	}
+	lastActivityWrites.Store(userID, now)
+
+	go func() {
+		usersCollection := mongodb.GetCollection("users")
+		if usersCollection == nil {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		_, err := usersCollection.UpdateOne(ctx, bson.M{"_id": userID}, bson.M{"$set": bson.M{"last_active_at": now}})
+		if err != nil {
+			log.Printf("Warning: Failed to record activity for user %s: %v", userID, err)
+		}
+	}()
+}
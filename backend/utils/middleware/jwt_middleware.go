@@ -48,6 +48,8 @@ func JWTMiddleware() echo.MiddlewareFunc {
 			c.Set("user_id", claims.UserID)
 			c.Set("user_email", claims.Email)
 
+			touchUserActivity(claims.UserID)
+
 			return next(c)
 		}
 	}
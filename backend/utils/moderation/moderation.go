@@ -0,0 +1,230 @@
+package moderation
+
+import (
+	_ "embed"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"google-devjam-backend/utils/nlp"
+)
+
+//go:embed data/blocklist.txt
+var blocklistData string
+
+const (
+	modeAlways = "always"
+	modeDense  = "dense"
+	modeKids   = "kids"
+)
+
+const (
+	// denseMaxRatio is the largest share of words a "dense" category may take up before an article is too graphic
+	denseMaxRatio = 0.01
+	// denseMinMentions keeps a single graphic word in a short text from counting as dense
+	denseMinMentions = 3
+	// kidSafeMaxMentions is how many times a "dense" or "kids" category may appear in a kid-safe article
+	kidSafeMaxMentions = 1
+	// shoutingMaxRatio is the largest share of long words written in capitals
+	shoutingMaxRatio = 0.3
+	// maxRepeatedSentences is how often one sentence may appear before the text counts as degenerate output
+	maxRepeatedSentences = 2
+)
+
+// blocklistCategory is a group of blocklist terms with the mode that decides when they fail an article
+type blocklistCategory struct {
+	name  string
+	mode  string
+	terms []string
+}
+
+var blocklist = loadBlocklist(blocklistData)
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	// phonePattern matches the shape of a phone number: an optional country code, an area code in parentheses or
+	// followed by a separator, then two groups of 3-4 digits. Digit counts are checked by containsPhoneNumber.
+	phonePattern      = regexp.MustCompile(`(\+\d{1,3}[\s.-]?)?(\(\d{1,4}\)[\s.-]?|\d{1,4}[\s.-])\d{3,4}[\s.-]?\d{3,4}`)
+	digitGroupPattern = regexp.MustCompile(`\d+`)
+)
+
+// Result is the outcome of a content check
+type Result struct {
+	Allowed    bool     `json:"allowed" bson:"allowed"`
+	Categories []string `json:"categories,omitempty" bson:"categories,omitempty"` // Categories that failed
+	Reasons    []string `json:"reasons,omitempty" bson:"reasons,omitempty"`       // One human-readable reason per failure
+}
+
+// Check runs the local blocklist and heuristics over text. kidSafe applies the stricter rules for young readers.
+func Check(text string, kidSafe bool) Result {
+	result := Result{Allowed: true}
+	fail := func(category, reason string) {
+		result.Allowed = false
+		result.Categories = append(result.Categories, category)
+		result.Reasons = append(result.Reasons, reason)
+	}
+
+	wordCount := len(nlp.Tokens(text))
+	for _, category := range blocklist {
+		matches := nlp.FindWordMatches(text, category.terms)
+		if len(matches) == 0 {
+			continue
+		}
+
+		switch category.mode {
+		case modeAlways:
+			fail(category.name, fmt.Sprintf("contains %s terms: %s", category.name, matchedTerms(matches)))
+		case modeDense:
+			if len(matches) >= denseMinMentions && float64(len(matches))/float64(wordCount) > denseMaxRatio {
+				fail(category.name, fmt.Sprintf("too much %s (%d of %d words): %s", category.name, len(matches), wordCount, matchedTerms(matches)))
+			} else if kidSafe && len(matches) > kidSafeMaxMentions {
+				fail(category.name, fmt.Sprintf("%s is not kid-safe: %s", category.name, matchedTerms(matches)))
+			}
+		case modeKids:
+			if kidSafe && len(matches) > kidSafeMaxMentions {
+				fail(category.name, fmt.Sprintf("%s is not kid-safe: %s", category.name, matchedTerms(matches)))
+			}
+		}
+	}
+
+	if kidSafe && (emailPattern.MatchString(text) || containsPhoneNumber(text)) {
+		fail("contact_details", "contains an email address or phone number")
+	}
+
+	if ratio := shoutingRatio(text); ratio > shoutingMaxRatio {
+		fail("shouting", fmt.Sprintf("%.0f%% of words are in capitals", ratio*100))
+	}
+
+	if sentence, count := mostRepeatedSentence(text); count > maxRepeatedSentences {
+		fail("repetition", fmt.Sprintf("sentence repeated %d times: %q", count, sentence))
+	}
+
+	return result
+}
+
+// containsPhoneNumber reports whether text has a phone-shaped number of 10-15 digits that is not part of a longer
+// number. Dates like 2024-01-15 and amounts like 1 000 000 have too few digits or the wrong grouping to match,
+// and runs of years like "1990 2000 2010" are skipped.
+func containsPhoneNumber(text string) bool {
+	for _, loc := range phonePattern.FindAllStringIndex(text, -1) {
+		start, end := loc[0], loc[1]
+		if continuesNumber(text, start, -1) || continuesNumber(text, end, 1) {
+			continue
+		}
+
+		match := text[start:end]
+		groups := digitGroupPattern.FindAllString(match, -1)
+		digits := len(strings.Join(groups, ""))
+		if digits >= 10 && digits <= 15 && !allYears(groups) {
+			return true
+		}
+	}
+	return false
+}
+
+// continuesNumber reports whether the number ending or starting at i goes on in the given direction, either directly
+// or after one separator, as in a card number or a longer list of figures
+func continuesNumber(text string, i, direction int) bool {
+	if direction < 0 {
+		i--
+	}
+	if i < 0 || i >= len(text) {
+		return false
+	}
+	if isDigit(text[i]) {
+		return true
+	}
+	next := i + direction
+	return strings.IndexByte(" .-", text[i]) >= 0 && next >= 0 && next < len(text) && isDigit(text[next])
+}
+
+// allYears reports whether every digit group is a year from 1900 to 2099
+func allYears(groups []string) bool {
+	for _, group := range groups {
+		if len(group) != 4 || (!strings.HasPrefix(group, "19") && !strings.HasPrefix(group, "20")) {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+// matchedTerms lists the distinct blocklist terms that matched, for logging
+func matchedTerms(matches []nlp.WordMatch) string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, match := range matches {
+		if !seen[match.Word] {
+			seen[match.Word] = true
+			terms = append(terms, match.Word)
+		}
+	}
+	sort.Strings(terms)
+	return strings.Join(terms, ", ")
+}
+
+// shoutingRatio returns the share of words of three or more letters that are written entirely in capitals
+func shoutingRatio(text string) float64 {
+	long, upper := 0, 0
+	for _, token := range nlp.Tokens(text) {
+		if len([]rune(token.Text)) < 3 {
+			continue
+		}
+		long++
+		if strings.ToUpper(token.Text) == token.Text && strings.ToLower(token.Text) != token.Text {
+			upper++
+		}
+	}
+	if long < 20 {
+		return 0 // Too short to judge; headlines and acronyms are fine
+	}
+	return float64(upper) / float64(long)
+}
+
+// mostRepeatedSentence returns the sentence that occurs most often, ignoring case, and how often it occurs
+func mostRepeatedSentence(text string) (string, int) {
+	counts := make(map[string]int)
+	best, bestCount := "", 0
+	for _, sentence := range nlp.Sentences(text) {
+		key := strings.ToLower(strings.Join(strings.Fields(sentence.Text), " "))
+		if len(nlp.Tokens(key)) < 4 {
+			continue // Short interjections like "So, anyway." repeat naturally
+		}
+		counts[key]++
+		if counts[key] > bestCount {
+			best, bestCount = sentence.Text, counts[key]
+		}
+	}
+	return best, bestCount
+}
+
+// loadBlocklist parses "[category mode]" headers followed by one term per line, skipping blank lines and # comments
+func loadBlocklist(data string) []blocklistCategory {
+	var categories []blocklistCategory
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.ToLower(strings.TrimSpace(line))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			fields := strings.Fields(strings.Trim(line, "[]"))
+			if len(fields) != 2 {
+				panic("moderation: invalid blocklist header " + line)
+			}
+			categories = append(categories, blocklistCategory{name: fields[0], mode: fields[1]})
+			continue
+		}
+
+		if len(categories) == 0 {
+			panic("moderation: blocklist term before any category: " + line)
+		}
+		current := &categories[len(categories)-1]
+		current.terms = append(current.terms, line)
+	}
+	return categories
+}
@@ -0,0 +1,166 @@
+package moderation
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// filler returns n distinct, harmless sentences of ten words each
+func filler(n int) string {
+	sentences := make([]string, n)
+	for i := range sentences {
+		sentences[i] = fmt.Sprintf("On day %d the city library welcomed many curious young readers.", i+1)
+	}
+	return strings.Join(sentences, " ")
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		wantDense []string // Failed categories in the default mode; nil means allowed
+		wantKids  []string // Failed categories in kid-safe mode
+	}{
+		{
+			name: "clean article",
+			text: filler(10),
+		},
+		{
+			name:      "profanity fails everywhere",
+			text:      filler(10) + " The mayor said the plan was bullshit.",
+			wantDense: []string{"profanity"},
+			wantKids:  []string{"profanity"},
+		},
+		{
+			name:      "explicit sexual content fails everywhere",
+			text:      filler(10) + " The site sold a celebrity sex tape.",
+			wantDense: []string{"sexual"},
+			wantKids:  []string{"sexual"},
+		},
+		{
+			name:     "news about prostitution is only kept from kids",
+			text:     filler(10) + " Police broke up a prostitution ring. Two men were charged with running prostitution websites.",
+			wantKids: []string{"sexual_topics"},
+		},
+		{
+			name:     "news about pornography is only kept from kids",
+			text:     filler(10) + " The law bans pornography near schools. Sellers of pornographic magazines face fines.",
+			wantKids: []string{"sexual_topics"},
+		},
+		{
+			name: "one mention of a kids topic is allowed in kid-safe mode",
+			text: filler(10) + " The museum shows a rifle from the old fort.",
+		},
+		{
+			name:     "repeated violence is kept from kids",
+			text:     filler(10) + " A gunman was arrested after the attack. Police said the gunman acted alone.",
+			wantKids: []string{"violence"},
+		},
+		{
+			name:      "dense graphic violence fails everywhere",
+			text:      filler(10) + " Soldiers tortured prisoners. The torture lasted weeks. Survivors described the torture.",
+			wantDense: []string{"graphic_violence"},
+			wantKids:  []string{"graphic_violence"},
+		},
+		{
+			name:     "sparse graphic violence is only kept from kids",
+			text:     filler(40) + " Historians say the prisoners faced torture. Few survived the torture.",
+			wantKids: []string{"graphic_violence"},
+		},
+		{
+			name:     "phone numbers are kept from kids",
+			text:     filler(10) + " Readers can call the library at (555) 123-4567.",
+			wantKids: []string{"contact_details"},
+		},
+		{
+			name:     "email addresses are kept from kids",
+			text:     filler(10) + " Readers can write to help@library.example.com for details.",
+			wantKids: []string{"contact_details"},
+		},
+		{
+			name: "dates and amounts are not contact details",
+			text: filler(10) + " On 2024-01-15 the city spent $1 000 000 on 250 000 books, and 2023-12-31 was the deadline.",
+		},
+		{
+			name:      "shouting fails everywhere",
+			text:      strings.ToUpper(filler(3)) + " " + filler(3),
+			wantDense: []string{"shouting"},
+			wantKids:  []string{"shouting"},
+		},
+		{
+			name: "a capitalized headline and acronyms are not shouting",
+			text: "LIBRARY OPENS. " + filler(10) + " NASA and the UN sent letters.",
+		},
+		{
+			name:      "repetition fails everywhere",
+			text:      filler(5) + strings.Repeat(" The library is open every single day.", 3),
+			wantDense: []string{"repetition"},
+			wantKids:  []string{"repetition"},
+		},
+		{
+			name: "a sentence used twice is not repetition",
+			text: filler(5) + strings.Repeat(" The library is open every single day.", 2),
+		},
+		{
+			name: "short interjections may repeat",
+			text: filler(5) + strings.Repeat(" So, anyway.", 4),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, mode := range []struct {
+				kidSafe bool
+				want    []string
+			}{
+				{false, tt.wantDense},
+				{true, tt.wantKids},
+			} {
+				result := Check(tt.text, mode.kidSafe)
+				if result.Allowed != (len(mode.want) == 0) || strings.Join(result.Categories, ",") != strings.Join(mode.want, ",") {
+					t.Errorf("Check(kidSafe=%v) = allowed %v, categories %v, reasons %v; want categories %v",
+						mode.kidSafe, result.Allowed, result.Categories, result.Reasons, mode.want)
+				}
+			}
+		})
+	}
+}
+
+func TestContainsPhoneNumber(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		// Real phone numbers
+		{"Call (555) 123-4567 today.", true},
+		{"Call 555-123-4567 today.", true},
+		{"Call 555.123.4567 today.", true},
+		{"Call +1 555 123 4567 today.", true},
+		{"Call +44 20 7946 0958 today.", true},
+		{"Call 020 7946 0958 today.", true},
+		{"Call +886-2-2345-6789 today.", true},
+		{"Call 02-2345-6789 today.", true},
+		{"Call 555-123-4567. Then wait.", true},
+
+		// Dates
+		{"It opened on 2024-01-15.", false},
+		{"It ran from 2023-12-31 to 2024-01-15.", false},
+		{"It opened on 15.01.2024 at noon.", false},
+		{"The years 1990 2000 2010 were busy.", false},
+
+		// Amounts and other numbers
+		{"The city spent 1 000 000 dollars.", false},
+		{"The city spent $12,500,000 on roads.", false},
+		{"About 250 000 people visited.", false},
+		{"The book's ISBN is 978-3-16-148410-0.", false},
+		{"The serial number is 12345678901234567890.", false},
+		{"Order 1234 5678 9012 3456 7890 was late.", false},
+	}
+
+	for _, tt := range tests {
+		if got := containsPhoneNumber(tt.text); got != tt.want {
+			t.Errorf("containsPhoneNumber(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
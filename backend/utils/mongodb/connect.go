@@ -2,11 +2,13 @@ package mongodb
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -53,6 +55,85 @@ func Connect() error {
 	return nil
 }
 
+// EnsureIndexes creates the unique indexes the application relies on to prevent duplicate records
+func EnsureIndexes() error {
+	if Database == nil {
+		return fmt.Errorf("database is not initialized")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	indexes := map[string][]mongo.IndexModel{
+		"words": {
+			{
+				Keys:    bson.D{{Key: "word", Value: 1}},
+				Options: options.Index().SetUnique(true).SetName("word_unique"),
+			},
+		},
+		"user_words": {
+			{
+				Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "word_id", Value: 1}},
+				Options: options.Index().SetUnique(true).SetName("user_word_unique"),
+			},
+		},
+		"recommend_words": {
+			{
+				Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "word_id", Value: 1}},
+				Options: options.Index().SetUnique(true).SetName("user_recommend_word_unique"),
+			},
+		},
+		"users": {
+			{
+				Keys:    bson.D{{Key: "email", Value: 1}},
+				Options: options.Index().SetUnique(true).SetName("email_unique"),
+			},
+		},
+		"news_pool_deliveries": {
+			{
+				Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "pool_id", Value: 1}},
+				Options: options.Index().SetUnique(true).SetName("user_pool_article_unique"),
+			},
+		},
+		"feed_tokens": {
+			{
+				Keys:    bson.D{{Key: "user_id", Value: 1}},
+				Options: options.Index().SetUnique(true).SetName("user_feed_token_unique"),
+			},
+			{
+				Keys:    bson.D{{Key: "token_hash", Value: 1}},
+				Options: options.Index().SetUnique(true).SetName("token_hash_unique"),
+			},
+		},
+		"source_articles": {
+			{
+				Keys:    bson.D{{Key: "url", Value: 1}},
+				Options: options.Index().SetUnique(true).SetName("url_unique"),
+			},
+		},
+		"news_pool": {
+			{
+				Keys:    bson.D{{Key: "level", Value: 1}, {Key: "created_at", Value: -1}},
+				Options: options.Index().SetName("level_created_at"),
+			},
+		},
+	}
+
+	var errs []error
+	for collectionName, models := range indexes {
+		if _, err := Database.Collection(collectionName).Indexes().CreateMany(ctx, models); err != nil {
+			// Existing duplicates make index creation fail; report them but keep creating the other indexes
+			errs = append(errs, fmt.Errorf("failed to create indexes on %s: %v", collectionName, err))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	log.Println("MongoDB indexes ensured")
+	return nil
+}
+
 // Disconnect closes the MongoDB connection
 func Disconnect() error {
 	if Client == nil {
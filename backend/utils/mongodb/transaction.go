@@ -0,0 +1,120 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	transactionsOnce      sync.Once
+	transactionsSupported bool
+)
+
+// maxTransactionAttempts bounds how often a transaction that lost a unique index race is run again
+const maxTransactionAttempts = 3
+
+// Tx carries the context for writes that belong together. When the deployment does not support
+// transactions, writes register compensations that undo them if a later step fails.
+type Tx struct {
+	ctx           context.Context
+	transactional bool
+	compensations []func(ctx context.Context) error
+}
+
+// Context returns the context every write in the transaction must use
+func (t *Tx) Context() context.Context {
+	return t.ctx
+}
+
+// InTransaction reports whether writes run inside a real transaction. A duplicate key error aborts a real
+// transaction, so writes must return it instead of retrying; WithTransaction then runs the whole function again.
+func (t *Tx) InTransaction() bool {
+	return t.transactional
+}
+
+// OnRollback registers an undo step for a write that just succeeded.
+// Inside a real transaction the database rolls back on its own, so the step is ignored.
+func (t *Tx) OnRollback(undo func(ctx context.Context) error) {
+	if t.transactional {
+		return
+	}
+	t.compensations = append(t.compensations, undo)
+}
+
+// SupportsTransactions reports whether the deployment is a replica set or sharded cluster
+func SupportsTransactions() bool {
+	transactionsOnce.Do(func() {
+		if Database == nil {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		var result bson.M
+		err := Database.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&result)
+		if err != nil {
+			// Servers older than 4.4.2 only know the legacy command name
+			err = Database.RunCommand(ctx, bson.D{{Key: "isMaster", Value: 1}}).Decode(&result)
+		}
+		if err != nil {
+			log.Printf("Warning: Failed to detect MongoDB topology, transactions disabled: %v", err)
+			return
+		}
+
+		_, isReplicaSet := result["setName"]
+		isSharded := result["msg"] == "isdbgrid"
+		transactionsSupported = isReplicaSet || isSharded
+		log.Printf("MongoDB transactions supported: %v", transactionsSupported)
+	})
+	return transactionsSupported
+}
+
+// WithTransaction runs fn inside a MongoDB transaction when the deployment supports it.
+// A transaction that fails with a duplicate key error lost a race against a concurrent write, so it is run again
+// and fn then sees the other write's result.
+// Otherwise fn runs directly and, if it fails, the compensations it registered run in reverse order.
+func WithTransaction(ctx context.Context, fn func(tx *Tx) error) error {
+	if Client == nil {
+		return mongo.ErrClientDisconnected
+	}
+
+	if SupportsTransactions() {
+		session, err := Client.StartSession()
+		if err != nil {
+			return fmt.Errorf("failed to start session: %v", err)
+		}
+		defer session.EndSession(ctx)
+
+		for attempt := 1; attempt <= maxTransactionAttempts; attempt++ {
+			_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+				return nil, fn(&Tx{ctx: sessCtx, transactional: true})
+			})
+			if !mongo.IsDuplicateKeyError(err) {
+				break
+			}
+		}
+		return err
+	}
+
+	tx := &Tx{ctx: ctx}
+	err := fn(tx)
+	if err != nil {
+		// Undo with a fresh context so a cancelled request still cleans up after itself
+		undoCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		for i := len(tx.compensations) - 1; i >= 0; i-- {
+			if undoErr := tx.compensations[i](undoCtx); undoErr != nil {
+				log.Printf("Warning: Failed to compensate partial write: %v", undoErr)
+			}
+		}
+	}
+	return err
+}
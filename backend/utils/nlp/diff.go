@@ -0,0 +1,109 @@
+package nlp
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	DiffEqual  = "equal"
+	DiffInsert = "insert" // Present in the answer but not in the expected text
+	DiffDelete = "delete" // Expected but missing from the answer
+)
+
+// maxDiffRunes caps the inputs to the quadratic diff so a pasted essay cannot stall a request
+const maxDiffRunes = 1000
+
+// DiffSegment is a run of characters that the expected text and the answer agree or disagree on
+type DiffSegment struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// NormalizeForComparison lowercases text, drops punctuation and collapses whitespace,
+// so answers are not marked wrong for capitalization or a missing period
+func NormalizeForComparison(text string) string {
+	var b strings.Builder
+	lastSpace := true
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case r == '\'' || r == '’':
+			// Keep apostrophes so "its" and "it's" still differ
+			b.WriteRune('\'')
+			lastSpace = false
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			continue
+		case unicode.IsSpace(r):
+			if !lastSpace {
+				b.WriteRune(' ')
+				lastSpace = true
+			}
+		default:
+			b.WriteRune(r)
+			lastSpace = false
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// CharDiff returns a character-level diff turning expected into actual, based on the longest common subsequence
+func CharDiff(expected, actual string) []DiffSegment {
+	a := []rune(expected)
+	b := []rune(actual)
+	if len(a) > maxDiffRunes {
+		a = a[:maxDiffRunes]
+	}
+	if len(b) > maxDiffRunes {
+		b = b[:maxDiffRunes]
+	}
+
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var segments []DiffSegment
+	appendRune := func(op string, r rune) {
+		if n := len(segments); n > 0 && segments[n-1].Op == op {
+			segments[n-1].Text += string(r)
+			return
+		}
+		segments = append(segments, DiffSegment{Op: op, Text: string(r)})
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			appendRune(DiffEqual, a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			appendRune(DiffDelete, a[i])
+			i++
+		default:
+			appendRune(DiffInsert, b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		appendRune(DiffDelete, a[i])
+	}
+	for ; j < len(b); j++ {
+		appendRune(DiffInsert, b[j])
+	}
+
+	return segments
+}
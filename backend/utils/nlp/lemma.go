@@ -0,0 +1,169 @@
+package nlp
+
+import "strings"
+
+// irregularForms maps common irregular inflections to their base form.
+// Forms that are also headwords in their own right ("left" as in "the left side", "better", "best") are left out,
+// so they are saved as themselves rather than turned into a different word.
+var irregularForms = map[string]string{
+	"am": "be", "is": "be", "are": "be", "was": "be", "were": "be", "been": "be", "being": "be",
+	"has": "have", "had": "have", "having": "have",
+	"does": "do", "did": "do", "done": "do", "doing": "do",
+	"went": "go", "gone": "go", "goes": "go",
+	"ran": "run", "ate": "eat", "eaten": "eat",
+	"saw": "see", "seen": "see", "came": "come", "took": "take", "taken": "take",
+	"gave": "give", "given": "give", "made": "make", "said": "say", "got": "get", "gotten": "get",
+	"knew": "know", "known": "know", "thought": "think", "told": "tell", "found": "find",
+	"felt": "feel", "kept": "keep", "began": "begin", "begun": "begin",
+	"brought": "bring", "bought": "buy", "caught": "catch", "taught": "teach", "fought": "fight",
+	"wrote": "write", "written": "write", "spoke": "speak", "spoken": "speak",
+	"broke": "break", "broken": "break", "chose": "choose", "chosen": "choose",
+	"drove": "drive", "driven": "drive", "rode": "ride", "ridden": "ride",
+	"rose": "rise", "risen": "rise", "fell": "fall", "fallen": "fall",
+	"grew": "grow", "grown": "grow", "threw": "throw", "thrown": "throw",
+	"flew": "fly", "flown": "fly", "drew": "draw", "drawn": "draw",
+	"sang": "sing", "sung": "sing", "swam": "swim", "swum": "swim",
+	"drank": "drink", "drunk": "drink", "wore": "wear", "worn": "wear",
+	"stood": "stand", "understood": "understand", "held": "hold", "built": "build",
+	"sent": "send", "spent": "spend", "lost": "lose", "met": "meet", "paid": "pay",
+	"sold": "sell", "slept": "sleep", "led": "lead", "meant": "mean", "heard": "hear",
+	"won": "win", "sat": "sit", "lay": "lie", "lain": "lie", "forgot": "forget", "forgotten": "forget",
+	"children": "child", "men": "man", "women": "woman", "people": "person",
+	"feet": "foot", "teeth": "tooth", "mice": "mouse", "geese": "goose",
+}
+
+// invariantWords end in an inflection-looking suffix but are already base forms
+var invariantWords = map[string]bool{
+	"news": true, "series": true, "species": true, "always": true, "perhaps": true,
+	"this": true, "his": true, "its": true, "thus": true, "yes": true, "bus": true,
+	"gas": true, "plus": true, "less": true, "unless": true, "during": true,
+	"nothing": true, "something": true, "anything": true, "everything": true,
+	"morning": true, "evening": true, "ceiling": true, "king": true, "thing": true,
+	"ring": true, "spring": true, "string": true, "bring": true, "sing": true,
+	"need": true, "feed": true, "seed": true, "speed": true, "bed": true, "red": true,
+}
+
+// Lemmatize returns the most likely dictionary form of an English word
+func Lemmatize(word string) string {
+	candidates := LemmaCandidates(word)
+	if len(candidates) > 1 {
+		// The first candidate is the word itself; prefer the first reduced form
+		return candidates[1]
+	}
+	if len(candidates) == 1 {
+		return candidates[0]
+	}
+	return ""
+}
+
+// LemmaCandidates returns possible base forms of a word, most likely first after the word itself.
+// Callers that can check a dictionary should try each candidate in order.
+func LemmaCandidates(word string) []string {
+	word = strings.ToLower(strings.TrimSpace(word))
+	word = strings.Trim(word, "'’")
+	if word == "" {
+		return nil
+	}
+
+	candidates := []string{word}
+	add := func(c string) {
+		if len(c) < 2 {
+			return
+		}
+		for _, existing := range candidates {
+			if existing == c {
+				return
+			}
+		}
+		candidates = append(candidates, c)
+	}
+
+	if base, ok := irregularForms[word]; ok {
+		add(base)
+		return candidates
+	}
+	if invariantWords[word] || len(word) <= 3 {
+		return candidates
+	}
+
+	// Possessives
+	if strings.HasSuffix(word, "'s") || strings.HasSuffix(word, "’s") {
+		add(strings.TrimSuffix(strings.TrimSuffix(word, "'s"), "’s"))
+		return candidates
+	}
+
+	switch {
+	case strings.HasSuffix(word, "ies") && len(word) > 4:
+		add(word[:len(word)-3] + "y")
+	case strings.HasSuffix(word, "ied") && len(word) > 4:
+		add(word[:len(word)-3] + "y")
+	case strings.HasSuffix(word, "sses"):
+		add(word[:len(word)-2])
+	case strings.HasSuffix(word, "ches"), strings.HasSuffix(word, "shes"),
+		strings.HasSuffix(word, "xes"), strings.HasSuffix(word, "zes"), strings.HasSuffix(word, "oes"):
+		add(word[:len(word)-2])
+		add(word[:len(word)-1])
+	case strings.HasSuffix(word, "ss"), strings.HasSuffix(word, "us"), strings.HasSuffix(word, "is"):
+		// Already a base form (glass, status, analysis)
+	case strings.HasSuffix(word, "s"):
+		add(word[:len(word)-1])
+	case strings.HasSuffix(word, "ing") && len(word) > 5:
+		addVerbStem(word[:len(word)-3], add)
+	case strings.HasSuffix(word, "ed") && len(word) > 4:
+		addVerbStem(word[:len(word)-2], add)
+		// Verbs ending in e only drop the d (used -> use)
+		add(word[:len(word)-1])
+	}
+
+	return candidates
+}
+
+// addVerbStem adds the likely base forms for a verb stem left after removing -ing or -ed
+func addVerbStem(stem string, add func(string)) {
+	n := len(stem)
+	if n >= 4 && stem[n-1] == stem[n-2] && isConsonant(stem[n-1]) && !strings.ContainsRune("lsz", rune(stem[n-1])) &&
+		!isConsonant(stem[n-3]) && isConsonant(stem[n-4]) {
+		// Doubled final consonant (running -> run, stopped -> stop)
+		add(stem[:n-1])
+		return
+	}
+	if n >= 3 && isConsonant(stem[n-1]) && !isConsonant(stem[n-2]) && isConsonant(stem[n-3]) &&
+		stem[n-1] != 'w' && stem[n-1] != 'x' && stem[n-1] != 'y' {
+		// Short consonant-vowel-consonant stems usually dropped a silent e (making -> make)
+		if vowelCount(stem) == 1 {
+			add(stem + "e")
+			add(stem)
+			return
+		}
+		add(stem)
+		add(stem + "e")
+		return
+	}
+	if n >= 2 && (strings.HasSuffix(stem, "v") || strings.HasSuffix(stem, "c") || strings.HasSuffix(stem, "z") ||
+		strings.HasSuffix(stem, "us") || strings.HasSuffix(stem, "ur")) {
+		// These endings almost always take an e (having, producing, using)
+		add(stem + "e")
+		add(stem)
+		return
+	}
+	add(stem)
+	add(stem + "e")
+}
+
+func vowelCount(s string) int {
+	count := 0
+	for i := 0; i < len(s); i++ {
+		if !isConsonant(s[i]) {
+			count++
+		}
+	}
+	return count
+}
+
+func isConsonant(b byte) bool {
+	switch b {
+	case 'a', 'e', 'i', 'o', 'u':
+		return false
+	}
+	return b >= 'a' && b <= 'z'
+}
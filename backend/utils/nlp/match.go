@@ -0,0 +1,108 @@
+package nlp
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// WordMatch is an occurrence of a target word or phrase in a text, possibly inflected
+type WordMatch struct {
+	Word string `json:"word" bson:"word"` // The target word as given, not the text that matched
+	Span `bson:",inline"`
+}
+
+// Tokens splits text into words of letters and digits, keeping apostrophes inside words (don't, student's).
+// Hyphens separate words, so "well-known" yields "well" and "known".
+func Tokens(text string) []Span {
+	runes := []rune(text)
+	var tokens []Span
+
+	start := -1
+	for i := 0; i <= len(runes); i++ {
+		inWord := false
+		if i < len(runes) {
+			r := runes[i]
+			switch {
+			case unicode.IsLetter(r) || unicode.IsDigit(r):
+				inWord = true
+			case (r == '\'' || r == '’') && start >= 0 && i+1 < len(runes) && unicode.IsLetter(runes[i+1]):
+				inWord = true
+			}
+		}
+
+		if inWord && start < 0 {
+			start = i
+		} else if !inWord && start >= 0 {
+			tokens = append(tokens, Span{Start: start, End: i, Text: string(runes[start:i])})
+			start = -1
+		}
+	}
+
+	return tokens
+}
+
+// FindWordMatches finds every occurrence of the given words and phrases in text, matching inflected forms
+// ("studied" matches "study"). Matches never overlap: where two would, the earlier and then the longer one wins.
+// Results are ordered by position.
+func FindWordMatches(text string, words []string) []WordMatch {
+	runes := []rune(text)
+	tokens := Tokens(text)
+
+	// The base forms each token could be an inflection of
+	tokenForms := make([]map[string]bool, len(tokens))
+	for i, token := range tokens {
+		forms := make(map[string]bool)
+		for _, candidate := range LemmaCandidates(token.Text) {
+			forms[candidate] = true
+		}
+		tokenForms[i] = forms
+	}
+
+	var matches []WordMatch
+	for _, word := range words {
+		parts := Tokens(strings.ToLower(word))
+		if len(parts) == 0 {
+			continue
+		}
+
+		for i := 0; i+len(parts) <= len(tokens); i++ {
+			matched := true
+			for j, part := range parts {
+				forms := tokenForms[i+j]
+				if !forms[part.Text] && !forms[Lemmatize(part.Text)] {
+					matched = false
+					break
+				}
+			}
+			if !matched {
+				continue
+			}
+
+			start, end := tokens[i].Start, tokens[i+len(parts)-1].End
+			matches = append(matches, WordMatch{
+				Word: word,
+				Span: Span{Start: start, End: end, Text: string(runes[start:end])},
+			})
+		}
+	}
+
+	sort.SliceStable(matches, func(a, b int) bool {
+		if matches[a].Start != matches[b].Start {
+			return matches[a].Start < matches[b].Start
+		}
+		return matches[a].End > matches[b].End
+	})
+
+	result := matches[:0]
+	lastEnd := -1
+	for _, match := range matches {
+		if match.Start < lastEnd {
+			continue
+		}
+		result = append(result, match)
+		lastEnd = match.End
+	}
+
+	return result
+}
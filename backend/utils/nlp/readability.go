@@ -0,0 +1,219 @@
+package nlp
+
+import (
+	_ "embed"
+	"math"
+	"strings"
+	"unicode"
+)
+
+//go:embed data/common_words.txt
+var commonWordsData string
+
+// commonWords holds the base forms of the most frequent English words
+var commonWords = loadWordList(commonWordsData)
+
+// commonWordRanks maps each common word to its 1-based position in the frequency-ordered list
+var commonWordRanks = loadWordRanks(commonWordsData)
+
+// Readability describes how hard a text is to read
+type Readability struct {
+	FleschKincaidGrade  float64 `json:"flesch_kincaid_grade" bson:"flesch_kincaid_grade"` // US school grade needed to follow the text
+	FleschReadingEase   float64 `json:"flesch_reading_ease" bson:"flesch_reading_ease"`   // 0-100, higher is easier
+	AvgSentenceLength   float64 `json:"avg_sentence_length" bson:"avg_sentence_length"`   // Words per sentence
+	AvgSyllablesPerWord float64 `json:"avg_syllables_per_word" bson:"avg_syllables_per_word"`
+	RareWordRatio       float64 `json:"rare_word_ratio" bson:"rare_word_ratio"`       // Share of words outside the common word list
+	UnknownWordRatio    float64 `json:"unknown_word_ratio" bson:"unknown_word_ratio"` // Share of words neither common nor known to the reader
+	WordCount           int     `json:"word_count" bson:"word_count"`
+	SentenceCount       int     `json:"sentence_count" bson:"sentence_count"`
+	Level               int     `json:"level" bson:"level"` // 1-10 learning level the metrics map to
+}
+
+// MeasureReadability computes readability metrics for text. knownWords are lowercase base forms the reader
+// already knows (e.g. their saved vocabulary); pass nil to treat only common words as known.
+func MeasureReadability(text string, knownWords map[string]bool) Readability {
+	var r Readability
+
+	sentences := Sentences(text)
+	for _, sentence := range sentences {
+		if len(countableWords(sentence.Text)) > 0 {
+			r.SentenceCount++
+		}
+	}
+
+	words := countableWords(text)
+	r.WordCount = len(words)
+	if r.WordCount == 0 || r.SentenceCount == 0 {
+		r.Level = 1
+		return r
+	}
+
+	syllables, rare, unknown := 0, 0, 0
+	for _, word := range words {
+		syllables += CountSyllables(word)
+
+		candidates := LemmaCandidates(word)
+		if !anyInSet(candidates, commonWords) {
+			rare++
+			if !anyInSet(candidates, knownWords) {
+				unknown++
+			}
+		}
+	}
+
+	wordCount := float64(r.WordCount)
+	r.AvgSentenceLength = wordCount / float64(r.SentenceCount)
+	r.AvgSyllablesPerWord = float64(syllables) / wordCount
+	r.FleschKincaidGrade = 0.39*r.AvgSentenceLength + 11.8*r.AvgSyllablesPerWord - 15.59
+	r.FleschReadingEase = 206.835 - 1.015*r.AvgSentenceLength - 84.6*r.AvgSyllablesPerWord
+	r.RareWordRatio = float64(rare) / wordCount
+	r.UnknownWordRatio = float64(unknown) / wordCount
+	r.Level = readabilityLevel(r.FleschKincaidGrade, r.RareWordRatio)
+
+	round2 := func(v float64) float64 { return math.Round(v*100) / 100 }
+	r.FleschKincaidGrade = round2(r.FleschKincaidGrade)
+	r.FleschReadingEase = round2(r.FleschReadingEase)
+	r.AvgSentenceLength = round2(r.AvgSentenceLength)
+	r.AvgSyllablesPerWord = round2(r.AvgSyllablesPerWord)
+	r.RareWordRatio = round2(r.RareWordRatio)
+	r.UnknownWordRatio = round2(r.UnknownWordRatio)
+
+	return r
+}
+
+// readabilityLevel maps a Flesch-Kincaid grade onto the 1-10 learning scale (grade 1 is level 1, grade 14 is level 10),
+// then nudges it by vocabulary: texts with unusually many rare words read harder than their sentence shape suggests
+func readabilityLevel(grade, rareRatio float64) int {
+	level := 1 + (grade-1)*9/13
+	// Around 12% rare words is typical for plain news writing
+	level += (rareRatio - 0.12) * 10
+
+	return int(math.Max(1, math.Min(10, math.Round(level))))
+}
+
+// CountSyllables estimates the syllables in an English word by counting vowel groups
+func CountSyllables(word string) int {
+	word = strings.ToLower(word)
+	word = strings.TrimSuffix(strings.TrimSuffix(word, "'s"), "’s")
+
+	count := 0
+	prevVowel := false
+	for _, r := range word {
+		vowel := strings.ContainsRune("aeiouy", r)
+		if vowel && !prevVowel {
+			count++
+		}
+		prevVowel = vowel
+	}
+
+	n := len(word)
+	switch {
+	// Silent final e (make), but not -le after a consonant (table)
+	case n > 2 && word[n-1] == 'e' && !(word[n-2] == 'l' && !strings.ContainsRune("aeiouy", rune(word[n-3]))) && !strings.ContainsRune("aeiouy", rune(word[n-2])):
+		count--
+	// -ed is silent except after t or d (jumped, but wanted)
+	case n > 3 && strings.HasSuffix(word, "ed") && word[n-3] != 't' && word[n-3] != 'd' && !strings.ContainsRune("aeiouy", rune(word[n-3])):
+		count--
+	}
+
+	if count < 1 {
+		return 1
+	}
+	return count
+}
+
+// countableWords returns the words of text that contain a letter, so numbers do not count as words
+func countableWords(text string) []string {
+	var words []string
+	for _, token := range Tokens(text) {
+		for _, r := range token.Text {
+			if unicode.IsLetter(r) {
+				words = append(words, token.Text)
+				break
+			}
+		}
+	}
+	return words
+}
+
+func anyInSet(candidates []string, set map[string]bool) bool {
+	for _, candidate := range candidates {
+		if set[candidate] {
+			return true
+		}
+	}
+	return false
+}
+
+// loadWordList parses one word per line, skipping blank lines and # comments
+func loadWordList(data string) map[string]bool {
+	words := make(map[string]bool)
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.ToLower(strings.TrimSpace(line))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words[line] = true
+	}
+	return words
+}
+
+// loadWordRanks parses a frequency-ordered word list like loadWordList, keeping each word's first position
+func loadWordRanks(data string) map[string]int {
+	ranks := make(map[string]int)
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.ToLower(strings.TrimSpace(line))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if _, ok := ranks[line]; !ok {
+			ranks[line] = len(ranks) + 1
+		}
+	}
+	return ranks
+}
+
+// levelWordRank returns how many of the most frequent common words a learner at level (1-10) is assumed to know.
+// Lower levels know a smaller share of the list; level 0 means unknown and assumes the whole list.
+func levelWordRank(level int) int {
+	if level <= 0 || level >= 10 {
+		return len(commonWordRanks)
+	}
+	return len(commonWordRanks) * level / 10
+}
+
+// HardWords returns the distinct words of text that are above a learner's level, in order of appearance:
+// words outside the part of the common word list the level covers and not in knownWords.
+// Capitalized words after the first are treated as names and skipped.
+func HardWords(text string, knownWords map[string]bool, level int) []string {
+	var hard []string
+	seen := make(map[string]bool)
+	maxRank := levelWordRank(level)
+	withinLevel := func(candidates []string) bool {
+		for _, candidate := range candidates {
+			if rank, ok := commonWordRanks[candidate]; ok && rank <= maxRank {
+				return true
+			}
+		}
+		return false
+	}
+
+	for i, word := range countableWords(text) {
+		if i > 0 && unicode.IsUpper([]rune(word)[0]) {
+			continue
+		}
+
+		candidates := LemmaCandidates(word)
+		if len(candidates) == 0 || withinLevel(candidates) || anyInSet(candidates, knownWords) {
+			continue
+		}
+
+		key := candidates[0]
+		if !seen[key] {
+			seen[key] = true
+			hard = append(hard, word)
+		}
+	}
+
+	return hard
+}
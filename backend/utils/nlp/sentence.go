@@ -0,0 +1,97 @@
+package nlp
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Span is a piece of text located by character (rune) offsets, End exclusive
+type Span struct {
+	Start int    `json:"start" bson:"start"`
+	End   int    `json:"end" bson:"end"`
+	Text  string `json:"text" bson:"text"`
+}
+
+// abbreviations that end with a period but do not end a sentence
+var abbreviations = map[string]bool{
+	"mr": true, "mrs": true, "ms": true, "dr": true, "prof": true, "sr": true, "jr": true,
+	"st": true, "vs": true, "etc": true, "e.g": true, "i.e": true, "u.s": true, "u.k": true,
+	"inc": true, "ltd": true, "co": true, "no": true,
+}
+
+// Sentences splits text into sentences, returning each with its rune offsets in the original text
+func Sentences(text string) []Span {
+	runes := []rune(text)
+	var spans []Span
+
+	start := 0
+	flush := func(end int) {
+		// Trim surrounding whitespace while keeping offsets accurate
+		s, e := start, end
+		for s < e && unicode.IsSpace(runes[s]) {
+			s++
+		}
+		for e > s && unicode.IsSpace(runes[e-1]) {
+			e--
+		}
+		if s < e {
+			spans = append(spans, Span{Start: s, End: e, Text: string(runes[s:e])})
+		}
+		start = end
+	}
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if r == '\n' {
+			flush(i + 1)
+			continue
+		}
+		if r != '.' && r != '!' && r != '?' {
+			continue
+		}
+
+		// Swallow repeated terminators and closing quotes/brackets
+		end := i + 1
+		for end < len(runes) && strings.ContainsRune(".!?\"'”’)]", runes[end]) {
+			end++
+		}
+		if end < len(runes) && !unicode.IsSpace(runes[end]) {
+			// Decimal numbers, URLs and the like
+			i = end - 1
+			continue
+		}
+		if r == '.' && isAbbreviation(runes, i) {
+			i = end - 1
+			continue
+		}
+		flush(end)
+		i = end - 1
+	}
+	flush(len(runes))
+
+	return spans
+}
+
+// SentenceAt returns the sentence containing the given rune offset
+func SentenceAt(text string, offset int) (Span, bool) {
+	for _, span := range Sentences(text) {
+		if offset >= span.Start && offset < span.End {
+			return span, true
+		}
+	}
+	return Span{}, false
+}
+
+// isAbbreviation reports whether the period at index i ends a known abbreviation or an initial
+func isAbbreviation(runes []rune, i int) bool {
+	j := i
+	for j > 0 && !unicode.IsSpace(runes[j-1]) {
+		j--
+	}
+	word := strings.ToLower(strings.TrimLeft(string(runes[j:i]), "\"'“‘("))
+	if abbreviations[word] {
+		return true
+	}
+	// Single capital letter initials like "J. K. Rowling"
+	return i-j == 1 && unicode.IsUpper(runes[j])
+}
@@ -82,6 +82,51 @@ func (c *Client) UploadAudio(audioData []byte, filename string, newsID string) (
 	return objectKey, publicURL, nil
 }
 
+// UploadAudioToKey uploads audio data under an explicit object key and returns its public URL path
+func (c *Client) UploadAudioToKey(audioData []byte, objectKey string) (string, error) {
+	publicURL, err := c.UploadObject(audioData, objectKey, "audio/wav")
+	if err != nil {
+		return "", fmt.Errorf("failed to upload audio to S3: %v", err)
+	}
+	return publicURL, nil
+}
+
+// UploadObject uploads data with the given content type under an explicit object key and returns its public URL path
+func (c *Client) UploadObject(data []byte, objectKey string, contentType string) (string, error) {
+	ctx := context.Background()
+	reader := bytes.NewReader(data)
+
+	_, err := c.minioClient.PutObject(ctx, c.bucketName, objectKey, reader, int64(len(data)), minio.PutObjectOptions{
+		ContentType: contentType,
+		UserMetadata: map[string]string{
+			"created-at": time.Now().Format(time.RFC3339),
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return c.PublicURL(objectKey), nil
+}
+
+// ObjectExists reports whether an object with the given key is stored in the bucket
+func (c *Client) ObjectExists(objectKey string) (bool, error) {
+	ctx := context.Background()
+	_, err := c.minioClient.StatObject(ctx, c.bucketName, objectKey, minio.StatObjectOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to stat object: %v", err)
+	}
+	return true, nil
+}
+
+// PublicURL returns the path-only public URL of an object (no hostname/protocol)
+func (c *Client) PublicURL(objectKey string) string {
+	return "/" + c.bucketName + "/" + objectKey
+}
+
 // DeleteAudio deletes an audio file from S3
 func (c *Client) DeleteAudio(objectKey string) error {
 	ctx := context.Background()
@@ -1,10 +1,16 @@
 package services
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"log"
 	"os"
+	"strings"
+	"sync"
 
+	"google-devjam-backend/model"
+	"google-devjam-backend/utils/nlp"
 	"google-devjam-backend/utils/s3"
 	"google-devjam-backend/utils/tts"
 )
@@ -14,6 +20,31 @@ type AudioService struct {
 	s3Client  *s3.Client
 }
 
+var (
+	sharedAudioMu      sync.Mutex
+	sharedAudioService *AudioService
+)
+
+// SharedAudioService returns a process-wide audio service, creating it on first use.
+// Creating a service probes the TTS ports and checks the bucket, so callers that synthesize often should reuse this one.
+// A failed creation is not cached, so a TTS service that comes up later is picked up on the next call.
+func SharedAudioService() (*AudioService, error) {
+	sharedAudioMu.Lock()
+	defer sharedAudioMu.Unlock()
+
+	if sharedAudioService != nil {
+		return sharedAudioService, nil
+	}
+
+	audioService, err := NewAudioService()
+	if err != nil {
+		return nil, err
+	}
+	sharedAudioService = audioService
+
+	return sharedAudioService, nil
+}
+
 // NewAudioService creates a new audio service with TTS and S3 clients
 func NewAudioService() (*AudioService, error) {
 	// Initialize TTS client with Mac GPU support
@@ -47,18 +78,8 @@ func NewAudioService() (*AudioService, error) {
 	ttsClient := tts.NewClient(ttsURL)
 
 	// Initialize S3 client
-	minioEndpoint := getEnvOrDefault("MINIO_ENDPOINT", "")
-	if minioEndpoint == "" {
-		// Check if we're in Docker environment
-		if _, inDocker := os.LookupEnv("DOCKER_ENV"); inDocker {
-			minioEndpoint = "minio:9000" // Use Docker service name
-		} else {
-			minioEndpoint = "localhost:9002" // Use correct port mapping for development
-		}
-	}
-
 	s3Config := s3.Config{
-		Endpoint:        minioEndpoint,
+		Endpoint:        minioEndpoint(),
 		AccessKeyID:     getEnvOrDefault("MINIO_ACCESS_KEY", "minioadmin"),
 		SecretAccessKey: getEnvOrDefault("MINIO_SECRET_KEY", "minioadmin123"),
 		BucketName:      getEnvOrDefault("MINIO_BUCKET", "devjam-audio"),
@@ -101,6 +122,101 @@ func (a *AudioService) GenerateAndStoreAudio(text string, newsID string) (audioU
 	return publicURL, objectKey, nil
 }
 
+// TimedAudio is stored article audio together with its captions and word timings
+type TimedAudio struct {
+	AudioURL    string
+	AudioKey    string
+	CaptionsURL string
+	CaptionsKey string
+	Sentences   []model.TimedSentence
+}
+
+// GenerateAndStoreTimedAudio converts text to speech, places every sentence and word on the audio timeline and stores
+// the audio and a WebVTT captions track in S3. Word times are estimated within each sentence.
+// Servers without timed synthesis fall back to plain synthesis with estimated sentence times. Caption failures are
+// logged and the audio is still returned, without captions.
+func (a *AudioService) GenerateAndStoreTimedAudio(text string, newsID string) (*TimedAudio, error) {
+	if err := a.ttsClient.HealthCheck(); err != nil {
+		return nil, fmt.Errorf("TTS service is not available: %v", err)
+	}
+
+	log.Printf("Generating timed audio for news ID: %s", newsID)
+	var sentenceTexts []string
+	for _, sentence := range nlp.Sentences(text) {
+		sentenceTexts = append(sentenceTexts, sentence.Text)
+	}
+
+	audioData, filename, timings, err := a.ttsClient.GenerateTimedAudio(sentenceTexts)
+	if err != nil {
+		if err != tts.ErrTimingUnsupported {
+			log.Printf("Timed synthesis failed for news %s, falling back to estimated timings: %v", newsID, err)
+		}
+		timings = nil
+		audioData, filename, err = a.ttsClient.GenerateAudio(text)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate audio: %v", err)
+		}
+	}
+
+	objectKey, publicURL, err := a.s3Client.UploadAudio(audioData, filename, newsID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload audio to S3: %v", err)
+	}
+	result := &TimedAudio{AudioURL: publicURL, AudioKey: objectKey}
+
+	sentences, err := tts.Align(text, audioData, timings)
+	if err != nil {
+		log.Printf("Failed to time audio for news %s: %v", newsID, err)
+		return result, nil
+	}
+
+	captionsKey := fmt.Sprintf("news/%s/captions.vtt", newsID)
+	captionsURL, err := a.s3Client.UploadObject([]byte(tts.BuildWebVTT(sentences)), captionsKey, "text/vtt")
+	if err != nil {
+		log.Printf("Failed to upload captions for news %s: %v", newsID, err)
+		return result, nil
+	}
+
+	result.CaptionsURL = captionsURL
+	result.CaptionsKey = captionsKey
+	result.Sentences = sentences
+
+	log.Printf("Timed audio successfully stored. URL: %s, Captions: %s", publicURL, captionsURL)
+	return result, nil
+}
+
+// GetOrCreateCachedAudio returns stored audio for the text, synthesizing and caching it in S3 on first use.
+// The object key is derived from the text, so identical words and sentences share one audio file.
+func (a *AudioService) GetOrCreateCachedAudio(text string) (audioURL string, audioKey string, err error) {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(text)))
+	objectKey := "tts/" + hex.EncodeToString(sum[:]) + ".wav"
+
+	exists, err := a.s3Client.ObjectExists(objectKey)
+	if err != nil {
+		return "", "", err
+	}
+	if exists {
+		return a.s3Client.PublicURL(objectKey), objectKey, nil
+	}
+
+	if err := a.ttsClient.HealthCheck(); err != nil {
+		return "", "", fmt.Errorf("TTS service is not available: %v", err)
+	}
+
+	audioData, _, err := a.ttsClient.GenerateAudio(text)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate audio: %v", err)
+	}
+
+	publicURL, err := a.s3Client.UploadAudioToKey(audioData, objectKey)
+	if err != nil {
+		return "", "", err
+	}
+
+	log.Printf("Cached TTS audio stored. URL: %s, Key: %s", publicURL, objectKey)
+	return publicURL, objectKey, nil
+}
+
 // DeleteAudio removes audio file from S3
 func (a *AudioService) DeleteAudio(audioKey string) error {
 	return a.s3Client.DeleteAudio(audioKey)
@@ -112,6 +228,44 @@ func (a *AudioService) GeneratePresignedURL(audioKey string) (string, error) {
 	return a.s3Client.GeneratePresignedURL(audioKey, 24*3600)
 }
 
+// minioEndpoint returns the host:port of the MinIO API
+func minioEndpoint() string {
+	endpoint := getEnvOrDefault("MINIO_ENDPOINT", "")
+	if endpoint == "" {
+		// Check if we're in Docker environment
+		if _, inDocker := os.LookupEnv("DOCKER_ENV"); inDocker {
+			endpoint = "minio:9000" // Use Docker service name
+		} else {
+			endpoint = "localhost:9002" // Use correct port mapping for development
+		}
+	}
+	return endpoint
+}
+
+// PublicAudioConfigured reports whether PUBLIC_AUDIO_BASE_URL is set, so PublicAudioURL can build URLs
+func PublicAudioConfigured() bool {
+	return os.Getenv("PUBLIC_AUDIO_BASE_URL") != ""
+}
+
+// PublicAudioURL turns a stored audio path (/bucket/key) into an absolute URL for clients outside the app, such as
+// podcast players. PUBLIC_AUDIO_BASE_URL sets where the bucket is publicly reachable. Without it ok is false:
+// the MinIO endpoint the backend uses is an internal host that outside clients cannot reach.
+func PublicAudioURL(audioPath string) (publicURL string, ok bool) {
+	if audioPath == "" {
+		return "", false
+	}
+	if strings.Contains(audioPath, "://") {
+		return audioPath, true
+	}
+
+	baseURL := os.Getenv("PUBLIC_AUDIO_BASE_URL")
+	if baseURL == "" {
+		return "", false
+	}
+
+	return strings.TrimSuffix(baseURL, "/") + "/" + strings.TrimPrefix(audioPath, "/"), true
+}
+
 // getEnvOrDefault returns environment variable value or default if not set
 func getEnvOrDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
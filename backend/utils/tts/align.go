@@ -0,0 +1,254 @@
+package tts
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"google-devjam-backend/model"
+	"google-devjam-backend/utils/nlp"
+)
+
+const (
+	// alignFrameSeconds is the window used to measure loudness when looking for pauses
+	alignFrameSeconds = 0.02
+	// minPauseSeconds is the shortest quiet stretch treated as a pause between sentences
+	minPauseSeconds = 0.15
+	// silenceRatio is how quiet a frame must be, relative to the average loudness of speech, to count as silence
+	silenceRatio = 0.1
+)
+
+// wavAudio is decoded 16-bit PCM audio, mixed down to mono
+type wavAudio struct {
+	samples    []float64
+	sampleRate int
+	duration   float64
+}
+
+// Align places every sentence and word of text on the audio timeline.
+// Sentence timings from the TTS server are used when they cover every sentence. Otherwise sentence boundaries are
+// estimated from the sentence lengths and snapped to the nearest pause in the audio, and the sentences are marked
+// Estimated. Word times are always estimates: each sentence's time is spread across its words by syllable count.
+func Align(text string, wav []byte, serverTimings []SentenceTiming) ([]model.TimedSentence, error) {
+	spans := nlp.Sentences(text)
+	if len(spans) == 0 {
+		return nil, nil
+	}
+
+	var sentenceTimes [][2]float64
+	estimated := len(serverTimings) != len(spans)
+	if !estimated {
+		sentenceTimes = make([][2]float64, len(spans))
+		for i, timing := range serverTimings {
+			sentenceTimes[i] = [2]float64{timing.Start, timing.End}
+		}
+	} else {
+		audio, err := decodeWAV(wav)
+		if err != nil {
+			return nil, err
+		}
+		sentenceTimes = estimateSentenceTimes(spans, audio)
+	}
+
+	sentences := make([]model.TimedSentence, len(spans))
+	for i, span := range spans {
+		start, end := sentenceTimes[i][0], sentenceTimes[i][1]
+		tokens := nlp.Tokens(span.Text)
+
+		weights := make([]float64, len(tokens))
+		for j, token := range tokens {
+			weights[j] = float64(nlp.CountSyllables(token.Text))
+		}
+		words := make([]model.TimedWord, len(tokens))
+		for j, bounds := range distribute(start, end, weights) {
+			words[j] = timedWord(span, tokens[j], bounds[0], bounds[1])
+		}
+
+		sentences[i] = model.TimedSentence{
+			Text:      span.Text,
+			Start:     span.Start,
+			End:       span.End,
+			StartTime: round3(start),
+			EndTime:   round3(end),
+			Estimated: estimated,
+			Words:     words,
+		}
+	}
+
+	return sentences, nil
+}
+
+// estimateSentenceTimes estimates where each sentence starts and ends in the audio
+func estimateSentenceTimes(spans []nlp.Span, audio *wavAudio) [][2]float64 {
+	weights := make([]float64, len(spans))
+	for i, span := range spans {
+		for _, token := range nlp.Tokens(span.Text) {
+			weights[i] += float64(nlp.CountSyllables(token.Text))
+		}
+		weights[i] += 2 // Sentence-final pause
+	}
+	estimates := distribute(0, audio.duration, weights)
+
+	pauses := findPauses(audio)
+
+	// Snap each inner boundary to the closest pause that keeps boundaries in order
+	boundaries := make([]float64, len(spans)+1)
+	boundaries[len(spans)] = audio.duration
+	for i := 1; i < len(spans); i++ {
+		estimate := estimates[i][0]
+		best := estimate
+		bestDistance := math.Max(1.5, (estimates[i][1]-estimates[i-1][0])/2)
+		for _, pause := range pauses {
+			if pause <= boundaries[i-1] || pause >= audio.duration {
+				continue
+			}
+			if distance := math.Abs(pause - estimate); distance <= bestDistance {
+				best, bestDistance = pause, distance
+			}
+		}
+		if best <= boundaries[i-1] {
+			best = boundaries[i-1] + (audio.duration-boundaries[i-1])/float64(len(spans)-i+1)
+		}
+		boundaries[i] = best
+	}
+
+	times := make([][2]float64, len(spans))
+	for i := range spans {
+		times[i] = [2]float64{boundaries[i], boundaries[i+1]}
+	}
+	return times
+}
+
+// findPauses returns the midpoints, in seconds, of quiet stretches long enough to separate sentences
+func findPauses(audio *wavAudio) []float64 {
+	frameSize := int(float64(audio.sampleRate) * alignFrameSeconds)
+	if frameSize == 0 || len(audio.samples) < frameSize {
+		return nil
+	}
+
+	frameCount := len(audio.samples) / frameSize
+	loudness := make([]float64, frameCount)
+	total := 0.0
+	for f := 0; f < frameCount; f++ {
+		sum := 0.0
+		for _, sample := range audio.samples[f*frameSize : (f+1)*frameSize] {
+			sum += sample * sample
+		}
+		loudness[f] = math.Sqrt(sum / float64(frameSize))
+		total += loudness[f]
+	}
+	threshold := total / float64(frameCount) * silenceRatio
+
+	var pauses []float64
+	minFrames := int(math.Ceil(minPauseSeconds / alignFrameSeconds))
+	runStart := -1
+	for f := 0; f <= frameCount; f++ {
+		quiet := f < frameCount && loudness[f] < threshold
+		if quiet && runStart < 0 {
+			runStart = f
+		} else if !quiet && runStart >= 0 {
+			if f-runStart >= minFrames {
+				pauses = append(pauses, float64(runStart+f)/2*alignFrameSeconds)
+			}
+			runStart = -1
+		}
+	}
+
+	return pauses
+}
+
+// distribute splits the span from start to end into consecutive pieces proportional to weights
+func distribute(start, end float64, weights []float64) [][2]float64 {
+	total := 0.0
+	for _, w := range weights {
+		total += w
+	}
+
+	pieces := make([][2]float64, len(weights))
+	t := start
+	for i, w := range weights {
+		length := (end - start) / float64(len(weights))
+		if total > 0 {
+			length = (end - start) * w / total
+		}
+		pieces[i] = [2]float64{t, t + length}
+		t += length
+	}
+	return pieces
+}
+
+// decodeWAV reads a 16-bit PCM WAV file, mixing channels down to mono
+func decodeWAV(data []byte) (*wavAudio, error) {
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return nil, fmt.Errorf("audio is not a WAV file")
+	}
+
+	var channels, bitsPerSample int
+	var sampleRate int
+	var pcm []byte
+	for offset := 12; offset+8 <= len(data); {
+		chunkID := string(data[offset : offset+4])
+		chunkSize := int(binary.LittleEndian.Uint32(data[offset+4 : offset+8]))
+		body := offset + 8
+		if body+chunkSize > len(data) {
+			chunkSize = len(data) - body // Streamed WAVs may declare a wrong size for the last chunk
+		}
+
+		switch chunkID {
+		case "fmt ":
+			if chunkSize < 16 {
+				return nil, fmt.Errorf("invalid WAV format chunk")
+			}
+			if format := binary.LittleEndian.Uint16(data[body : body+2]); format != 1 {
+				return nil, fmt.Errorf("unsupported WAV encoding %d, only PCM is supported", format)
+			}
+			channels = int(binary.LittleEndian.Uint16(data[body+2 : body+4]))
+			sampleRate = int(binary.LittleEndian.Uint32(data[body+4 : body+8]))
+			bitsPerSample = int(binary.LittleEndian.Uint16(data[body+14 : body+16]))
+		case "data":
+			pcm = data[body : body+chunkSize]
+		}
+
+		offset = body + chunkSize + chunkSize%2 // Chunks are padded to an even size
+	}
+
+	if channels == 0 || sampleRate == 0 || pcm == nil {
+		return nil, fmt.Errorf("WAV file is missing its format or data")
+	}
+	if bitsPerSample != 16 {
+		return nil, fmt.Errorf("unsupported WAV sample size %d bits, only 16-bit is supported", bitsPerSample)
+	}
+
+	frameBytes := 2 * channels
+	samples := make([]float64, len(pcm)/frameBytes)
+	for i := range samples {
+		sum := 0.0
+		for ch := 0; ch < channels; ch++ {
+			at := i*frameBytes + ch*2
+			sum += float64(int16(binary.LittleEndian.Uint16(pcm[at:at+2]))) / 32768
+		}
+		samples[i] = sum / float64(channels)
+	}
+
+	return &wavAudio{
+		samples:    samples,
+		sampleRate: sampleRate,
+		duration:   float64(len(samples)) / float64(sampleRate),
+	}, nil
+}
+
+// timedWord converts a token of a sentence into a word located in the full text, with its estimated time in the audio
+func timedWord(sentence nlp.Span, token nlp.Span, start, end float64) model.TimedWord {
+	return model.TimedWord{
+		Text:               token.Text,
+		Start:              sentence.Start + token.Start,
+		End:                sentence.Start + token.End,
+		EstimatedStartTime: round3(start),
+		EstimatedEndTime:   round3(end),
+	}
+}
+
+// round3 rounds seconds to milliseconds
+func round3(seconds float64) float64 {
+	return math.Round(seconds*1000) / 1000
+}
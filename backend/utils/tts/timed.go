@@ -0,0 +1,76 @@
+package tts
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ErrTimingUnsupported is returned when the TTS server has no timed synthesis endpoint
+var ErrTimingUnsupported = errors.New("TTS service does not support timed synthesis")
+
+type TimedTTSRequest struct {
+	Sentences []string `json:"sentences"`
+}
+
+// SentenceTiming is when a sentence is spoken, in seconds from the start of the audio.
+// The server synthesizes sentences one at a time, so it knows sentence boundaries but not word boundaries.
+type SentenceTiming struct {
+	Index int     `json:"index"`
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+}
+
+type timedTTSResponse struct {
+	Audio     string           `json:"audio"` // Base64-encoded WAV
+	Filename  string           `json:"filename"`
+	Sentences []SentenceTiming `json:"sentences"`
+}
+
+// GenerateTimedAudio synthesizes the sentences as one recording and returns the audio with the time span of each sentence.
+// It returns ErrTimingUnsupported if the server predates the /api/tts/timed endpoint.
+func (c *Client) GenerateTimedAudio(sentences []string) ([]byte, string, []SentenceTiming, error) {
+	jsonData, err := json.Marshal(TimedTTSRequest{Sentences: sentences})
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	resp, err := c.HTTPClient.Post(
+		c.BaseURL+"/api/tts/timed",
+		"application/json",
+		bytes.NewBuffer(jsonData),
+	)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to make TTS request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusMethodNotAllowed {
+		return nil, "", nil, ErrTimingUnsupported
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, "", nil, fmt.Errorf("TTS request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var timedResp timedTTSResponse
+	if err := json.NewDecoder(resp.Body).Decode(&timedResp); err != nil {
+		return nil, "", nil, fmt.Errorf("failed to decode timed TTS response: %v", err)
+	}
+
+	audioData, err := base64.StdEncoding.DecodeString(timedResp.Audio)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to decode audio data: %v", err)
+	}
+
+	filename := timedResp.Filename
+	if filename == "" {
+		filename = "audio.wav"
+	}
+
+	return audioData, filename, timedResp.Sentences, nil
+}
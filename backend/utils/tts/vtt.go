@@ -0,0 +1,50 @@
+package tts
+
+import (
+	"fmt"
+	"strings"
+
+	"google-devjam-backend/model"
+)
+
+// BuildWebVTT renders timed sentences as a WebVTT track with one cue per sentence.
+// Each word is preceded by a tag with its estimated start time, so players and readers can highlight words roughly as
+// they are spoken.
+func BuildWebVTT(sentences []model.TimedSentence) string {
+	var b strings.Builder
+	b.WriteString("WEBVTT\n")
+
+	for i, sentence := range sentences {
+		fmt.Fprintf(&b, "\n%d\n%s --> %s\n", i+1, vttTimestamp(sentence.StartTime), vttTimestamp(sentence.EndTime))
+
+		runes := []rune(sentence.Text)
+		pos := 0
+		for _, word := range sentence.Words {
+			start, end := word.Start-sentence.Start, word.End-sentence.Start
+			if start < pos || end > len(runes) {
+				continue
+			}
+			b.WriteString(escapeVTT(string(runes[pos:start])))
+			fmt.Fprintf(&b, "<%s><c>%s</c>", vttTimestamp(word.EstimatedStartTime), escapeVTT(string(runes[start:end])))
+			pos = end
+		}
+		b.WriteString(escapeVTT(string(runes[pos:])))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// vttTimestamp formats seconds as HH:MM:SS.mmm
+func vttTimestamp(seconds float64) string {
+	ms := int64(seconds*1000 + 0.5)
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
+
+// escapeVTT escapes cue text and keeps it on one line, since a blank line would end the cue
+func escapeVTT(text string) string {
+	text = strings.ReplaceAll(text, "&", "&amp;")
+	text = strings.ReplaceAll(text, "<", "&lt;")
+	text = strings.ReplaceAll(text, ">", "&gt;")
+	return strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(text)
+}
@@ -3,10 +3,13 @@
 import { NewsCard } from "@/components/news-card";
 import { NewsLoadingAnimation } from "@/components/news-loading-animation";
 import { Button } from "@/components/ui/button";
-import { generateNews } from "@/lib/api/news";
+import { generateNews, getNews, getNewsJob, isNewsJobFinished } from "@/lib/api/news";
 import { useQuery } from "@tanstack/react-query";
 import { RefreshCw } from "lucide-react";
 
+// How often a running generation job is polled
+const JOB_POLL_INTERVAL_MS = 2000;
+
 export default function NewsPage() {
   const query = useQuery({
     queryKey: ["news"],
@@ -14,6 +17,34 @@ export default function NewsPage() {
     retry: 2
   });
 
+  // New articles are generated in the background; poll the job until it is done
+  const jobId = query.data?.job?.id;
+  const jobQuery = useQuery({
+    queryKey: ["news-job", jobId],
+    queryFn: () => getNewsJob(jobId!),
+    enabled: !!jobId,
+    refetchInterval: (jobQuery) => {
+      const job = jobQuery.state.data?.job;
+      return job && isNewsJobFinished(job) ? false : JOB_POLL_INTERVAL_MS;
+    }
+  });
+
+  const job = jobQuery.data?.job ?? query.data?.job;
+  const jobRunning = !!job && !isNewsJobFinished(job);
+  // Articles become readable once their text is stored, before their audio is ready
+  const readyCount = job?.articles.filter((article) => article.status === "audio" || article.status === "completed").length ?? 0;
+
+  // Reload the list each time another article of the job becomes readable
+  const listQuery = useQuery({
+    queryKey: ["news-list", jobId, readyCount, job?.status],
+    queryFn: () => getNews({ limit: 20 }),
+    enabled: !!jobId && (readyCount > 0 || !jobRunning),
+    placeholderData: (previous) => previous
+  });
+
+  const allNews = (jobId ? listQuery.data?.news : undefined) ?? query.data?.all_news ?? [];
+  const jobFailed = job?.status === "failed" && allNews.length === 0;
+
   const handleRetry = () => {
     query.refetch();
   };
@@ -25,9 +56,9 @@ export default function NewsPage() {
         <p className="text-sm text-muted-foreground mt-1">根據你的學習進度和興趣自動生成的個人化新聞內容。</p>
 
         <div className="mt-6">
-          {query.isLoading ? (
+          {query.isLoading || (jobRunning && allNews.length === 0) ? (
             <NewsLoadingAnimation />
-          ) : query.error ? (
+          ) : query.error || jobFailed ? (
             <div className="flex flex-col items-center justify-center p-8 text-center space-y-4">
               <p className="text-sm text-muted-foreground">無法生成新聞內容，請稍後再試。</p>
               <Button onClick={handleRetry} variant="outline" size="sm">
@@ -35,9 +66,14 @@ export default function NewsPage() {
                 重新生成
               </Button>
             </div>
-          ) : query.data?.all_news ? (
+          ) : allNews.length > 0 ? (
             <div className="flex flex-col gap-4">
-              {query.data.all_news.map((news) => (
+              {jobRunning && (
+                <p className="text-sm text-muted-foreground">
+                  正在生成新的新聞（{readyCount}/{job.articles.length}）...
+                </p>
+              )}
+              {allNews.map((news) => (
                 <NewsCard key={news.id} news={news} />
               ))}
             </div>
@@ -10,7 +10,7 @@ interface ApiError extends Error {
 
 // Request types
 type GetNewsParams = {
-  page?: number;
+  cursor?: string; // next_cursor from the previous page
   limit?: number;
   level?: number;
   search?: string;
@@ -18,14 +18,23 @@ type GetNewsParams = {
 
 // Response types
 type GenerateNewsResponse = {
-  all_news: News[];
+  all_news: News[] | null; // Null for a new user whose first articles are still being generated
+  job?: NewsJob; // Set when new articles are being generated in the background
+};
+
+type ForceGenerateNewsResponse = {
+  job: NewsJob;
+};
+
+type NewsJobResponse = {
+  job: NewsJob;
 };
 
 type GetNewsResponse = {
   news: News[];
   total: number;
-  page: number;
   limit: number;
+  next_cursor?: string; // Absent on the last page
 };
 
 type GetSingleNewsResponse = {
@@ -36,8 +45,8 @@ type GetSingleNewsResponse = {
 function buildQueryString(params: GetNewsParams): string {
   const searchParams = new URLSearchParams();
 
-  if (params.page !== undefined) {
-    searchParams.append("page", params.page.toString());
+  if (params.cursor !== undefined) {
+    searchParams.append("cursor", params.cursor);
   }
   if (params.limit !== undefined) {
     searchParams.append("limit", params.limit.toString());
@@ -71,7 +80,7 @@ export async function generateNews(): Promise<GenerateNewsResponse> {
   return responseData;
 }
 
-export async function forceGenerateNews(): Promise<GenerateNewsResponse> {
+export async function forceGenerateNews(): Promise<ForceGenerateNewsResponse> {
   const response = await fetch(`${baseUrl}/news/force-generate`, {
     credentials: "include",
     method: "POST",
@@ -89,6 +98,28 @@ export async function forceGenerateNews(): Promise<GenerateNewsResponse> {
   return responseData;
 }
 
+export async function getNewsJob(id: string): Promise<NewsJobResponse> {
+  const response = await fetch(`${baseUrl}/news/jobs/${id}`, {
+    credentials: "include",
+    method: "GET",
+    headers: createAuthHeaders()
+  });
+
+  if (!response.ok) {
+    const errorData = await response.json();
+    const error: ApiError = new Error(errorData.error || "Failed to get news job");
+    error.fullResponse = errorData;
+    throw error;
+  }
+
+  const responseData = await response.json();
+  return responseData;
+}
+
+export function isNewsJobFinished(job: NewsJob): boolean {
+  return job.status === "completed" || job.status === "failed";
+}
+
 export async function getNews(params?: GetNewsParams): Promise<GetNewsResponse> {
   const queryString = params ? buildQueryString(params) : "";
 
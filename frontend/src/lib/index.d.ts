@@ -55,3 +55,23 @@ type News = {
   created_at: string; // ISO date string
   updated_at: string; // ISO date string
 };
+
+type NewsJobArticle = {
+  status: "pending" | "generating" | "audio" | "completed" | "failed"; // "audio" means the text is already readable
+  news_id?: string;
+  title?: string;
+  error?: string;
+};
+
+type NewsJob = {
+  id: string;
+  user_id: string;
+  status: "pending" | "running" | "completed" | "failed";
+  force: boolean;
+  scheduled: boolean;
+  articles: NewsJobArticle[];
+  error?: string;
+  created_at: string; // ISO date string
+  updated_at: string; // ISO date string
+  finished_at?: string; // ISO date string
+};